@@ -32,6 +32,13 @@ type AnyType interface {
 	AnyType() string
 }
 
+// legacyAnyNames maps names generated by the former CamelToSnake to types, so that stored payloads can be decoded,
+// e.g. "httpserver" to HTTPServer which is named "http_server" now
+var legacyAnyNames = map[string]reflect.Type{}
+
+// prototypeToName keeps names of registered types, which are stable after RegisterAcronyms
+var prototypeToName = map[reflect.Type]string{}
+
 // Register bind typ with prototype
 // E.g.
 //		contents.Register("image", &contents.Image{})
@@ -43,7 +50,14 @@ func RegisterAny(prototype interface{}) error {
 		return errors.New("conflict type name: " + name)
 	}
 
-	nameToPrototype[name] = reflect.TypeOf(prototype)
+	typ := reflect.TypeOf(prototype)
+	nameToPrototype[name] = typ
+	prototypeToName[typ] = name
+	if _, ok := prototype.(AnyType); !ok {
+		if legacy := legacyCamelToSnake(indirectType(typ).Name()); legacy != name {
+			legacyAnyNames[legacy] = typ
+		}
+	}
 	return nil
 }
 
@@ -59,10 +73,42 @@ func GetAnyTypeName(prototype interface{}) string {
 	}
 
 	p := reflect.TypeOf(prototype)
+	mu.RLock()
+	name, ok := prototypeToName[p]
+	mu.RUnlock()
+	if ok {
+		return name
+	}
+	return CamelToSnake(indirectType(p).Name())
+}
+
+func indirectType(p reflect.Type) reflect.Type {
 	for p.Kind() == reflect.Ptr {
 		p = p.Elem()
 	}
-	return CamelToSnake(p.Name())
+	return p
+}
+
+// legacyCamelToSnake is CamelToSnake before acronyms were segmented, which only inserts _ before runs of upper case letters
+func legacyCamelToSnake(s string) string {
+	snake := make([]rune, 0, len(s)+1)
+	flag := false
+	k := 'a' - 'A'
+	for i, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			if !flag {
+				flag = true
+				if i > 0 {
+					snake = append(snake, '_')
+				}
+			}
+			snake = append(snake, c+k)
+		} else {
+			flag = false
+			snake = append(snake, c)
+		}
+	}
+	return string(snake)
 }
 
 func getProtoType(typ string) (reflect.Type, bool) {
@@ -70,9 +116,12 @@ func getProtoType(typ string) (reflect.Type, bool) {
 	defer mu.RUnlock()
 	if prototype, ok := nameToPrototype[typ]; ok {
 		return prototype, true
-	} else {
-		return nil, false
 	}
+
+	if prototype, ok := legacyAnyNames[typ]; ok {
+		return prototype, true
+	}
+	return nil, false
 }
 
 var _ sql.Scanner = (*Any)(nil)
@@ -81,6 +130,10 @@ var _ driver.Valuer = (*Any)(nil)
 type Any struct {
 	val     interface{}
 	jsonStr string
+
+	// typ is the type name decoded from JSON, which is kept in encoding even if the type is renamed,
+	// e.g. by a legacy name, so that decoded payloads are encoded the same
+	typ string
 }
 
 // NewAnyObj is for gomobile
@@ -101,6 +154,7 @@ func (a *Any) Val() interface{} {
 func (a *Any) SetVal(v interface{}) {
 	a.val = v
 	a.jsonStr = ""
+	a.typ = ""
 }
 
 func (a *Any) JSONString() string {
@@ -222,6 +276,7 @@ func (a *Any) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	a.SetVal(ptrVal.Elem().Interface())
+	a.typ = typ
 	return nil
 }
 
@@ -243,15 +298,24 @@ func (a *Any) MarshalJSON() ([]byte, error) {
 			return nil, err
 		}
 
-		err = json.Unmarshal(b, &m)
-		if err != nil {
-			return nil, err
+		if len(b) > 0 && b[0] == '{' {
+			err = json.Unmarshal(b, &m)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// struct with custom marshaler, e.g. Decimal
+			m[keyAnyVal] = json.RawMessage(b)
 		}
 	} else {
 		m[keyAnyVal] = a.val
 	}
 
-	m[keyAnyType] = a.TypeName()
+	if a.typ != "" {
+		m[keyAnyType] = a.typ
+	} else {
+		m[keyAnyType] = a.TypeName()
+	}
 	return json.Marshal(m)
 }
 
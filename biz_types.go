@@ -3,8 +3,13 @@ package gox
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/gopub/gox/protobuf/base"
@@ -77,6 +82,85 @@ func (n *PhoneNumber) String() string {
 	return fmt.Sprintf("+%d%d-%s", n.CountryCode, n.NationalNumber, n.Extension)
 }
 
+// ParsePhoneNumberIn parses s in international format, or in national format of region such as CN and US
+func ParsePhoneNumberIn(s string, region string) (*PhoneNumber, error) {
+	pn, err := phonenumbers.Parse(s, strings.ToUpper(region))
+	if err != nil {
+		return nil, err
+	}
+
+	if !phonenumbers.IsValidNumber(pn) {
+		return nil, errors.New("invalid phone number")
+	}
+
+	return &PhoneNumber{
+		CountryCode:    int(pn.GetCountryCode()),
+		NationalNumber: int64(pn.GetNationalNumber()),
+		Extension:      pn.GetExtension(),
+	}, nil
+}
+
+// E164 returns n in E.164 format without extension, e.g. +8613800138000
+func (n *PhoneNumber) E164() string {
+	return fmt.Sprintf("+%d%d", n.CountryCode, n.NationalNumber)
+}
+
+func (n *PhoneNumber) IsValid() bool {
+	pn, err := phonenumbers.Parse(n.E164(), "")
+	return err == nil && phonenumbers.IsValidNumber(pn)
+}
+
+type jsonPhoneNumber struct {
+	CountryCode    int    `json:"country_code"`
+	NationalNumber int64  `json:"national_number"`
+	Extension      string `json:"extension,omitempty"`
+	E164           string `json:"e164,omitempty"`
+}
+
+// MarshalJSON encodes n as an object with fields country_code, national_number, extension and e164
+func (n *PhoneNumber) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonPhoneNumber{
+		CountryCode:    n.CountryCode,
+		NationalNumber: n.NationalNumber,
+		Extension:      n.Extension,
+		E164:           n.E164(),
+	})
+}
+
+// UnmarshalJSON accepts an object encoded by MarshalJSON, or a string in international format such as "+86 138-0013-8000"
+func (n *PhoneNumber) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+
+		v, err := ParsePhoneNumber(s)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s into gox.PhoneNumber: %v", s, err)
+		}
+		*n = *v
+		return nil
+	}
+
+	var v jsonPhoneNumber
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	if len(v.E164) > 0 {
+		pn, err := ParsePhoneNumber(v.E164)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s into gox.PhoneNumber: %v", v.E164, err)
+		}
+		pn.Extension = v.Extension
+		*n = *pn
+		return nil
+	}
+	n.CountryCode, n.NationalNumber, n.Extension = v.CountryCode, v.NationalNumber, v.Extension
+	return nil
+}
+
 func (n *PhoneNumber) InternationalFormat() string {
 	pn, err := phonenumbers.Parse(n.String(), "")
 	if err != nil {
@@ -102,6 +186,7 @@ func (n *PhoneNumber) MaskString() string {
 	return fmt.Sprintf("+%d%s-%s", n.CountryCode, nn, n.Extension)
 }
 
+// Scan accepts the composite value returned by Value, and strings in international format such as +8613800138000
 func (n *PhoneNumber) Scan(src interface{}) error {
 	if src == nil {
 		return nil
@@ -116,6 +201,15 @@ func (n *PhoneNumber) Scan(src interface{}) error {
 		}
 	}
 
+	if ok && strings.HasPrefix(s, "+") {
+		v, err := ParsePhoneNumber(s)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s into gox.PhoneNumber: %v", s, err)
+		}
+		*n = *v
+		return nil
+	}
+
 	if !ok || len(s) < 10 {
 		return fmt.Errorf("failed to parse %v into gox.PhoneNumber", src)
 	}
@@ -269,12 +363,17 @@ func (c Currency) Upper() Currency {
 	return Currency(strings.ToUpper(string(c)))
 }
 
-// Money
+// Money is an amount in minor units of currency, e.g. cents for USD
 type Money struct {
 	Currency Currency `json:"currency"`
 	Amount   int64    `json:"amount"`
 }
 
+const (
+	ErrCurrencyMismatch ErrorString = "currency mismatch"
+	ErrMoneyOverflow    ErrorString = "amount overflow"
+)
+
 var _ driver.Valuer = (*Money)(nil)
 var _ sql.Scanner = (*Money)(nil)
 
@@ -282,21 +381,85 @@ func (m *Money) String() string {
 	return fmt.Sprintf("%s %d", m.Currency, m.Amount)
 }
 
+// Add returns m+o. It returns ErrCurrencyMismatch if currencies differ, or ErrMoneyOverflow.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency.Upper() != o.Currency.Upper() {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Currency: m.Currency, Amount: sum}, nil
+}
+
+// Sub returns m-o. It returns ErrCurrencyMismatch if currencies differ, or ErrMoneyOverflow.
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	o.Amount = -o.Amount
+	return m.Add(o)
+}
+
+// MulRatio returns m*num/den rounded half away from zero, e.g. m.MulRatio(15, 100) is 15% of m.
+// It panics if den is 0.
+func (m Money) MulRatio(num, den int64) (Money, error) {
+	if den == 0 {
+		panic("den is 0")
+	}
+
+	v := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	d := big.NewInt(den)
+	if den < 0 {
+		v.Neg(v)
+		d.Neg(d)
+	}
+
+	q, r := new(big.Int).QuoRem(v, d, new(big.Int))
+	// round half away from zero
+	if r.Abs(r).Lsh(r, 1).Cmp(d) >= 0 {
+		if v.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	if !q.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Currency: m.Currency, Amount: q.Int64()}, nil
+}
+
+// Scan accepts the composite value returned by Value, e.g. (USD,100)
 func (m *Money) Scan(src interface{}) error {
 	if src == nil {
 		return nil
 	}
 
-	b, ok := src.([]byte)
-	if !ok {
+	s, ok := src.(string)
+	if b, isBytes := src.([]byte); isBytes {
+		s, ok = string(b), true
+	}
+
+	if !ok || len(s) < 5 || s[0] != '(' || s[len(s)-1] != ')' {
 		return fmt.Errorf("failed to parse %v into gox.Money", src)
 	}
 
-	k, err := fmt.Sscanf(string(b), "(%s,%d)", &m.Currency, &m.Amount)
-	if k == 2 {
-		return nil
+	fields := strings.Split(s[1:len(s)-1], ",")
+	if len(fields) != 2 {
+		return fmt.Errorf("failed to parse %s into gox.Money", s)
 	}
-	return fmt.Errorf("failed to parse %v into gox.Money: %v", string(b), err)
+
+	amount, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Money: %v", s, err)
+	}
+	m.Currency = Currency(strings.TrimSpace(fields[0]))
+	m.Amount = amount
+	return nil
 }
 
 func (m *Money) Value() (driver.Value, error) {
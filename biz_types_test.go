@@ -0,0 +1,97 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneNumber_JSON(t *testing.T) {
+	pn, err := gox.ParsePhoneNumber("+86 13800138000")
+	require.NoError(t, err)
+	assert.Equal(t, "+8613800138000", pn.E164())
+	assert.True(t, pn.IsValid())
+
+	b, err := json.Marshal(pn)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"country_code":86,"national_number":13800138000,"e164":"+8613800138000"}`, string(b))
+
+	var v gox.PhoneNumber
+	require.NoError(t, json.Unmarshal(b, &v))
+	assert.Equal(t, *pn, v)
+
+	v = gox.PhoneNumber{}
+	require.NoError(t, json.Unmarshal([]byte(`"+86 (138) 00138000"`), &v))
+	assert.Equal(t, *pn, v)
+
+	v = gox.PhoneNumber{}
+	require.NoError(t, json.Unmarshal([]byte(`{"country_code":86,"national_number":13800138000}`), &v))
+	assert.Equal(t, *pn, v)
+
+	assert.Error(t, json.Unmarshal([]byte(`"12345"`), &v))
+}
+
+func TestPhoneNumber_SQL(t *testing.T) {
+	pn, err := gox.ParsePhoneNumber("+8613800138000")
+	require.NoError(t, err)
+	val, err := pn.Value()
+	require.NoError(t, err)
+
+	var v gox.PhoneNumber
+	require.NoError(t, v.Scan([]byte(val.(string))))
+	assert.Equal(t, *pn, v)
+
+	v = gox.PhoneNumber{}
+	require.NoError(t, v.Scan("+8613800138000"))
+	assert.Equal(t, *pn, v)
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := gox.Money{Currency: gox.USD, Amount: 1050}
+	b := gox.Money{Currency: gox.USD, Amount: 250}
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1300), sum.Amount)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-800), diff.Amount)
+
+	_, err = a.Add(gox.Money{Currency: gox.CNY, Amount: 1})
+	assert.Equal(t, gox.ErrCurrencyMismatch, err)
+
+	_, err = gox.Money{Currency: gox.USD, Amount: math.MaxInt64}.Add(gox.Money{Currency: gox.USD, Amount: 1})
+	assert.Equal(t, gox.ErrMoneyOverflow, err)
+	_, err = a.Sub(gox.Money{Currency: gox.USD, Amount: math.MinInt64})
+	assert.Equal(t, gox.ErrMoneyOverflow, err)
+	_, err = gox.Money{Currency: gox.USD, Amount: math.MaxInt64}.MulRatio(3, 2)
+	assert.Equal(t, gox.ErrMoneyOverflow, err)
+
+	v, err := a.MulRatio(15, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(158), v.Amount)
+
+	v, err = gox.Money{Currency: gox.USD, Amount: -1050}.MulRatio(15, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-158), v.Amount)
+
+	v, err = a.MulRatio(1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(350), v.Amount)
+}
+
+func TestMoney_SQL(t *testing.T) {
+	m := gox.Money{Currency: gox.USD, Amount: 100}
+	val, err := m.Value()
+	require.NoError(t, err)
+
+	var v gox.Money
+	require.NoError(t, v.Scan([]byte(val.(string))))
+	assert.Equal(t, m, v)
+	assert.Error(t, v.Scan("USD 100"))
+}
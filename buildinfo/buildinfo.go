@@ -0,0 +1,107 @@
+// Package buildinfo reports version information of the running binary.
+//
+// Version, Commit and BuildTime are expected to be set by the linker, e.g.
+//
+//	go build -ldflags "-X github.com/gopub/gox/buildinfo.Version=v1.0.0 -X github.com/gopub/gox/buildinfo.Commit=abc123"
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const pkgPath = "github.com/gopub/gox/buildinfo"
+
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+var startTime = time.Now()
+
+// Info is the version information of the running binary. It can be embedded in health responses.
+type Info struct {
+	Module    string    `json:"module,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Commit    string    `json:"commit,omitempty"`
+	BuildTime string    `json:"build_time,omitempty"`
+	GoVersion string    `json:"go_version"`
+	StartTime time.Time `json:"start_time"`
+}
+
+// Get returns build info. Module and version are read from the binary if they are not set by ldflags
+func Get() *Info {
+	info := &Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		StartTime: startTime,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.Module = bi.Main.Path
+		if len(info.Version) == 0 && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+	}
+	return info
+}
+
+func (i *Info) String() string {
+	b := &strings.Builder{}
+	if len(i.Module) > 0 {
+		b.WriteString(i.Module)
+		b.WriteString(" ")
+	}
+
+	if len(i.Version) > 0 {
+		b.WriteString(i.Version)
+	} else {
+		b.WriteString("unknown version")
+	}
+
+	if len(i.Commit) > 0 {
+		fmt.Fprintf(b, " (%s)", i.Commit)
+	}
+
+	if len(i.BuildTime) > 0 {
+		fmt.Fprintf(b, " built at %s", i.BuildTime)
+	}
+
+	fmt.Fprintf(b, " with %s", i.GoVersion)
+	return b.String()
+}
+
+// Banner returns a startup banner for service name
+func Banner(name string) string {
+	info := Get()
+	line := strings.Repeat("=", 60)
+	return fmt.Sprintf("%s\n%s\n%s\nstarted at %s\n%s", line, name, info, info.StartTime.Format(time.RFC3339), line)
+}
+
+// LDFlags returns linker flags which set version, commit and build time.
+// If buildTime is zero, current time is used.
+func LDFlags(version, commit string, buildTime time.Time) string {
+	if buildTime.IsZero() {
+		buildTime = time.Now()
+	}
+	return fmt.Sprintf("-X %s.Version=%s -X %s.Commit=%s -X %s.BuildTime=%s",
+		pkgPath, version, pkgPath, commit, pkgPath, buildTime.UTC().Format(time.RFC3339))
+}
+
+// Handler serves build info as JSON
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Get()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package buildinfo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox/buildinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setVars(version, commit, buildTime string) func() {
+	v, c, b := buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime
+	buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = version, commit, buildTime
+	return func() {
+		buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = v, c, b
+	}
+}
+
+func TestGet(t *testing.T) {
+	defer setVars("v1.2.3", "abc123", "2020-01-01T00:00:00Z")()
+	info := buildinfo.Get()
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Equal(t, "2020-01-01T00:00:00Z", info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.False(t, info.StartTime.IsZero())
+	assert.Equal(t, info.StartTime, buildinfo.Get().StartTime)
+}
+
+func TestInfo_String(t *testing.T) {
+	info := &buildinfo.Info{
+		Module:    "github.com/a/b",
+		Version:   "v1.2.3",
+		Commit:    "abc123",
+		BuildTime: "2020-01-01T00:00:00Z",
+		GoVersion: "go1.12",
+	}
+	assert.Equal(t, "github.com/a/b v1.2.3 (abc123) built at 2020-01-01T00:00:00Z with go1.12", info.String())
+	assert.Equal(t, "unknown version with go1.12", (&buildinfo.Info{GoVersion: "go1.12"}).String())
+}
+
+func TestBanner(t *testing.T) {
+	defer setVars("v1.2.3", "", "")()
+	s := buildinfo.Banner("api")
+	assert.Contains(t, s, "\napi\n")
+	assert.Contains(t, s, "v1.2.3")
+}
+
+func TestLDFlags(t *testing.T) {
+	s := buildinfo.LDFlags("v1.0.0", "abc", time.Date(2020, 1, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600)))
+	assert.Equal(t, "-X github.com/gopub/gox/buildinfo.Version=v1.0.0 -X github.com/gopub/gox/buildinfo.Commit=abc"+
+		" -X github.com/gopub/gox/buildinfo.BuildTime=2020-01-01T00:00:00Z", s)
+}
+
+func TestLDFlags_Build(t *testing.T) {
+	if testing.Short() {
+		t.Skip("building a binary")
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go isn't found")
+	}
+
+	flags := buildinfo.LDFlags("v9.9.9", "def456", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	out, err := exec.Command(goBin, "run", "-ldflags", flags, "./testdata/version").CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Contains(t, string(out), "v9.9.9 (def456) built at 2020-01-01T00:00:00Z with go")
+}
+
+func TestHandler(t *testing.T) {
+	defer setVars("v1.2.3", "abc123", "")()
+	s := httptest.NewServer(buildinfo.Handler())
+	defer s.Close()
+
+	resp, err := http.Get(s.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json"))
+
+	var info buildinfo.Info
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	assert.Equal(t, "v1.2.3", info.Version)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Empty(t, info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.True(t, info.StartTime.Equal(buildinfo.Get().StartTime))
+}
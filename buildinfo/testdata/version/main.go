@@ -0,0 +1,12 @@
+// Command version prints build info, which is built with ldflags by buildinfo tests
+package main
+
+import (
+	"fmt"
+
+	"github.com/gopub/gox/buildinfo"
+)
+
+func main() {
+	fmt.Println(buildinfo.Get())
+}
@@ -0,0 +1,119 @@
+package gox
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// CIDRContains reports whether ip is in cidr, e.g. CIDRContains("10.0.0.0/8", "10.1.2.3").
+// It returns false if cidr or ip is invalid.
+func CIDRContains(cidr, ip string) bool {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+
+	addr := net.ParseIP(ip)
+	return addr != nil && n.Contains(addr)
+}
+
+// CIDRsOverlap reports whether cidr a and b share any address. It returns false if any of them is invalid.
+func CIDRsOverlap(a, b string) bool {
+	_, na, err := net.ParseCIDR(a)
+	if err != nil {
+		return false
+	}
+
+	_, nb, err := net.ParseCIDR(b)
+	if err != nil {
+		return false
+	}
+
+	// two cidr blocks either nest or are disjoint
+	return na.Contains(nb.IP) || nb.Contains(na.IP)
+}
+
+// maxSplitBits limits SplitCIDR to 65536 subnets
+const maxSplitBits = 16
+
+// SplitCIDR splits cidr into subnets with newPrefix, e.g. SplitCIDR("10.0.0.0/16", 18) returns
+// 10.0.0.0/18, 10.0.64.0/18, 10.0.128.0/18 and 10.0.192.0/18
+func SplitCIDR(cidr string, newPrefix int) ([]string, error) {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := n.Mask.Size()
+	if newPrefix < ones || newPrefix > bits {
+		return nil, fmt.Errorf("new prefix should be in [%d, %d]", ones, bits)
+	}
+
+	if newPrefix-ones > maxSplitBits {
+		return nil, fmt.Errorf("too many subnets: 2^%d", newPrefix-ones)
+	}
+
+	count := 1 << uint(newPrefix-ones)
+	res := make([]string, 0, count)
+	ip := append(net.IP(nil), n.IP...)
+	mask := net.CIDRMask(newPrefix, bits)
+	for i := 0; i < count; i++ {
+		sub := &net.IPNet{IP: append(net.IP(nil), ip...), Mask: mask}
+		res = append(res, sub.String())
+		addIP(ip, uint(bits-newPrefix))
+	}
+	return res, nil
+}
+
+// RangeCIDRHosts calls f with each host address in cidr in order until f returns false.
+// Network and broadcast addresses of IPv4 blocks are skipped, except in /31 and /32.
+// ip passed to f is reused, so it should be copied if retained.
+func RangeCIDRHosts(cidr string, f func(ip net.IP) bool) error {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	ones, bits := n.Mask.Size()
+	if bits == 0 {
+		return errors.New("invalid mask")
+	}
+
+	ip := append(net.IP(nil), n.IP...)
+	skipEnds := bits == 8*net.IPv4len && bits-ones > 1
+	if skipEnds {
+		addIP(ip, 0)
+	}
+
+	for n.Contains(ip) {
+		next := append(net.IP(nil), ip...)
+		overflow := addIP(next, 0)
+		if skipEnds && (overflow || !n.Contains(next)) {
+			// ip is the broadcast address
+			return nil
+		}
+
+		if !f(ip) {
+			return nil
+		}
+
+		if overflow {
+			return nil
+		}
+		ip = next
+	}
+	return nil
+}
+
+// addIP adds 1<<shift to ip in place, and reports whether it overflows
+func addIP(ip net.IP, shift uint) bool {
+	i := len(ip) - 1 - int(shift/8)
+	carry := uint(1) << (shift % 8)
+	for ; i >= 0 && carry > 0; i-- {
+		v := uint(ip[i]) + carry
+		ip[i] = byte(v)
+		carry = v >> 8
+	}
+	return carry > 0
+}
@@ -0,0 +1,56 @@
+package gox_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIDR(t *testing.T) {
+	assert.True(t, gox.CIDRContains("10.0.0.0/8", "10.1.2.3"))
+	assert.False(t, gox.CIDRContains("10.0.0.0/8", "11.1.2.3"))
+	assert.True(t, gox.CIDRContains("2001:db8::/32", "2001:db8::1"))
+	assert.False(t, gox.CIDRContains("invalid", "10.1.2.3"))
+
+	assert.True(t, gox.CIDRsOverlap("10.0.0.0/8", "10.1.0.0/16"))
+	assert.True(t, gox.CIDRsOverlap("10.1.0.0/16", "10.0.0.0/8"))
+	assert.False(t, gox.CIDRsOverlap("10.0.0.0/16", "10.1.0.0/16"))
+
+	subnets, err := gox.SplitCIDR("10.0.0.0/16", 18)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/18", "10.0.64.0/18", "10.0.128.0/18", "10.0.192.0/18"}, subnets)
+	subnets, err = gox.SplitCIDR("2001:db8::/32", 34)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2001:db8::/34", "2001:db8:4000::/34", "2001:db8:8000::/34", "2001:db8:c000::/34"}, subnets)
+	_, err = gox.SplitCIDR("10.0.0.0/16", 8)
+	assert.Error(t, err)
+	_, err = gox.SplitCIDR("10.0.0.0/8", 30)
+	assert.Error(t, err)
+}
+
+func TestRangeCIDRHosts(t *testing.T) {
+	var hosts []string
+	err := gox.RangeCIDRHosts("192.168.1.0/30", func(ip net.IP) bool {
+		hosts = append(hosts, ip.String())
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.1", "192.168.1.2"}, hosts)
+
+	hosts = nil
+	gox.RangeCIDRHosts("255.255.255.254/31", func(ip net.IP) bool {
+		hosts = append(hosts, ip.String())
+		return true
+	})
+	assert.Equal(t, []string{"255.255.255.254", "255.255.255.255"}, hosts)
+
+	n := 0
+	gox.RangeCIDRHosts("10.0.0.0/8", func(ip net.IP) bool {
+		n++
+		return n < 300
+	})
+	assert.Equal(t, 300, n)
+}
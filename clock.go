@@ -1,6 +1,10 @@
 package gox
 
-import "time"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type Clock interface {
 	Now() time.Time
@@ -19,6 +23,58 @@ func LocalClock() Clock {
 	return lc
 }
 
+type clockBox struct {
+	Clock
+}
+
+var defaultClock atomic.Value
+
+func init() {
+	defaultClock.Store(clockBox{lc})
+}
+
+// DefaultClock returns the clock used by NextSecond, NextMilliseconds and id generators without their own clock
+func DefaultClock() Clock {
+	return defaultClock.Load().(clockBox).Clock
+}
+
+// SetDefaultClock replaces DefaultClock, e.g. with a MockClock in tests. Nil restores LocalClock.
+func SetDefaultClock(c Clock) {
+	if c == nil {
+		c = lc
+	}
+	defaultClock.Store(clockBox{c})
+}
+
+// MockClock is a Clock which only moves when it's set or advanced
+type MockClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewMockClock(now time.Time) *MockClock {
+	return &MockClock{now: now}
+}
+
+func (c *MockClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+func (c *MockClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// Advance moves clock forward by d, or backward if d is negative
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
 type Uptimer interface {
 	Uptime() float64
 }
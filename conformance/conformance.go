@@ -0,0 +1,225 @@
+// Package conformance publishes test vectors of wire formats defined by gox, so that ports in other languages
+// stay compatible with it. Vectors are in VectorsCSV with columns suite, input and expected, which can be
+// written to a file and loaded by the test suites of other ports. Run checks an implementation in go against them.
+//
+// Suites:
+//
+//	id_short          input is a decimal id, expected is ID.ShortString and vice versa
+//	id_pretty         input is a decimal id, expected is ID.PrettyString and vice versa
+//	id_short_invalid  input is not a valid short id
+//	any               input is an Any envelope, expected is the envelope encoded again, compared as JSON
+//	cursor            input is a decimal id, expected is the cursor token signed with CursorKey which never expires
+//	cursor_invalid    input is a token which must be rejected with CursorKey
+//	shard             input is "shardBitSize seqBitSize id", expected is "timestamp shardID seq" of the id
+package conformance
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gopub/gox"
+)
+
+// CursorKey is the key of cursor vectors
+const CursorKey = "gox-conformance-cursor-key"
+
+// Impl is the implementation under test
+type Impl interface {
+	ShortID(id int64) string
+	ParseShortID(s string) (int64, error)
+	PrettyID(id int64) string
+	ParsePrettyID(s string) (int64, error)
+
+	// RoundTripAny decodes an Any envelope and encodes it again
+	RoundTripAny(b []byte) ([]byte, error)
+
+	// EncodeCursor returns a token of id which never expires
+	EncodeCursor(key []byte, id int64) string
+	DecodeCursor(key []byte, token string) (int64, error)
+
+	DecomposeID(shardBitSize, seqBitSize uint, id int64) (timestamp, shardID, seq int64)
+}
+
+type Vector struct {
+	Suite    string
+	Input    string
+	Expected string
+}
+
+// Vectors returns vectors parsed from VectorsCSV
+func Vectors() []*Vector {
+	records, err := csv.NewReader(strings.NewReader(VectorsCSV)).ReadAll()
+	if err != nil {
+		panic(err)
+	}
+
+	l := make([]*Vector, 0, len(records))
+	for _, r := range records[1:] {
+		l = append(l, &Vector{Suite: r[0], Input: r[1], Expected: r[2]})
+	}
+	return l
+}
+
+// Reporter receives failures of Run, which is satisfied by *testing.T.
+// Package testing isn't imported here, as it registers its flags in binaries importing it.
+type Reporter interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Run checks impl against all vectors
+func Run(t Reporter, impl Impl) {
+	for i, v := range Vectors() {
+		name := fmt.Sprintf("%s/%d", v.Suite, i)
+		if err := check(impl, v); err != nil {
+			t.Errorf("%s: input=%q expected=%q: %v", name, v.Input, v.Expected, err)
+		}
+	}
+}
+
+func check(impl Impl, v *Vector) error {
+	switch v.Suite {
+	case "id_short":
+		return checkIDString(v, impl.ShortID, impl.ParseShortID)
+	case "id_pretty":
+		return checkIDString(v, impl.PrettyID, impl.ParsePrettyID)
+	case "id_short_invalid":
+		if id, err := impl.ParseShortID(v.Input); err == nil {
+			return fmt.Errorf("got %d, want error", id)
+		}
+		return nil
+	case "any":
+		b, err := impl.RoundTripAny([]byte(v.Input))
+		if err != nil {
+			return err
+		}
+		return checkJSON(b, []byte(v.Expected))
+	case "cursor":
+		id, err := strconv.ParseInt(v.Input, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if s := impl.EncodeCursor([]byte(CursorKey), id); s != v.Expected {
+			return fmt.Errorf("encoded %q", s)
+		}
+
+		decoded, err := impl.DecodeCursor([]byte(CursorKey), v.Expected)
+		if err != nil {
+			return err
+		}
+
+		if decoded != id {
+			return fmt.Errorf("decoded %d", decoded)
+		}
+		return nil
+	case "cursor_invalid":
+		if id, err := impl.DecodeCursor([]byte(CursorKey), v.Input); err == nil {
+			return fmt.Errorf("got %d, want error", id)
+		}
+		return nil
+	case "shard":
+		var shardBitSize, seqBitSize uint
+		var id int64
+		if _, err := fmt.Sscan(v.Input, &shardBitSize, &seqBitSize, &id); err != nil {
+			return err
+		}
+
+		ts, shardID, seq := impl.DecomposeID(shardBitSize, seqBitSize, id)
+		if s := fmt.Sprint(ts, shardID, seq); s != v.Expected {
+			return fmt.Errorf("got %q", s)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown suite %s", v.Suite)
+	}
+}
+
+func checkIDString(v *Vector, format func(int64) string, parse func(string) (int64, error)) error {
+	id, err := strconv.ParseInt(v.Input, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if s := format(id); s != v.Expected {
+		return fmt.Errorf("formatted %q", s)
+	}
+
+	parsed, err := parse(v.Expected)
+	if err != nil {
+		return err
+	}
+
+	if parsed != id {
+		return fmt.Errorf("parsed %d", parsed)
+	}
+	return nil
+}
+
+func checkJSON(b, expected []byte) error {
+	var x, y interface{}
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(expected, &y); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(x, y) {
+		return fmt.Errorf("got %s", bytes.TrimSpace(b))
+	}
+	return nil
+}
+
+type native struct{}
+
+// Native returns the implementation of gox
+func Native() Impl {
+	return native{}
+}
+
+func (native) ShortID(id int64) string {
+	return gox.ID(id).ShortString()
+}
+
+func (native) ParseShortID(s string) (int64, error) {
+	id, err := gox.ParseShortID(s)
+	return int64(id), err
+}
+
+func (native) PrettyID(id int64) string {
+	return gox.ID(id).PrettyString()
+}
+
+func (native) ParsePrettyID(s string) (int64, error) {
+	id, err := gox.ParsePrettyID(s)
+	return int64(id), err
+}
+
+func (native) RoundTripAny(b []byte) ([]byte, error) {
+	a := new(gox.Any)
+	if err := json.Unmarshal(b, a); err != nil {
+		return nil, err
+	}
+	return json.Marshal(a)
+}
+
+func (native) EncodeCursor(key []byte, id int64) string {
+	return gox.NewCursorCodec(key, 0).Encode(gox.ID(id))
+}
+
+func (native) DecodeCursor(key []byte, token string) (int64, error) {
+	id, err := gox.NewCursorCodec(key, 0).Decode(token)
+	return int64(id), err
+}
+
+func (native) DecomposeID(shardBitSize, seqBitSize uint, id int64) (int64, int64, int64) {
+	g := gox.NewSnakeIDGenerator(shardBitSize, seqBitSize, gox.NextMilliseconds, gox.FixedNumberGetter(0), nil)
+	c := g.Decompose(gox.ID(id))
+	return c.Timestamp, c.ShardID, c.Seq
+}
@@ -0,0 +1,33 @@
+package conformance_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox/conformance"
+)
+
+func TestNative(t *testing.T) {
+	conformance.Run(t, conformance.Native())
+}
+
+type errorRecorder []string
+
+func (r *errorRecorder) Errorf(format string, args ...interface{}) {
+	*r = append(*r, format)
+}
+
+type brokenImpl struct {
+	conformance.Impl
+}
+
+func (brokenImpl) ShortID(id int64) string {
+	return ""
+}
+
+func TestRun_ReportsFailures(t *testing.T) {
+	var r errorRecorder
+	conformance.Run(&r, brokenImpl{conformance.Native()})
+	if len(r) == 0 {
+		t.Fatal("expected failures")
+	}
+}
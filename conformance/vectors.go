@@ -0,0 +1,55 @@
+package conformance
+
+// VectorsCSV holds test vectors. Existing vectors must not be changed, as ports depend on them.
+const VectorsCSV = `suite,input,expected
+id_short,0,0
+id_short,9,9
+id_short,10,A
+id_short,35,Z
+id_short,36,a
+id_short,61,z
+id_short,62,10
+id_short,3843,zz
+id_short,2147483648,2LKcb2
+id_short,9007199254740991,fFgnDxSe7
+id_short,9223372036854775807,AzL8n0Y58m7
+id_short,163840382742659072,C6ODoeBFJ2
+id_pretty,1,2
+id_pretty,8,9
+id_pretty,9,A
+id_pretty,33,Z
+id_pretty,35,22
+id_pretty,2147483648,2E9ZSTS
+id_pretty,9007199254740991,5DCTFJDKDDX
+id_pretty,68,31
+id_pretty,1156,211
+id_pretty,163840382742659072,3CDFITCU5W19
+id_short_invalid,,
+id_short_invalid,a-b,
+id_short_invalid,12_3,
+id_short_invalid,ab cd,
+id_short_invalid,AzL8n0Y58m8,
+id_short_invalid,000000000001,
+any,"{""@t"":""image"",""url"":""https://example.com/a.png"",""w"":640,""h"":480,""fmt"":""png"",""size"":1024}","{""@t"":""image"",""fmt"":""png"",""h"":480,""size"":1024,""url"":""https://example.com/a.png"",""w"":640}"
+any,"{""@t"":""video"",""url"":""https://example.com/v.mp4"",""len"":30,""img"":{""url"":""https://example.com/v.jpg"",""w"":320}}","{""@t"":""video"",""img"":{""url"":""https://example.com/v.jpg"",""w"":320},""len"":30,""url"":""https://example.com/v.mp4""}"
+any,"{""@t"":""audio"",""url"":""https://example.com/a.mp3"",""fmt"":""mp3"",""len"":120}","{""@t"":""audio"",""fmt"":""mp3"",""len"":120,""url"":""https://example.com/a.mp3""}"
+any,"{""@t"":""file"",""url"":""https://example.com/f.pdf"",""name"":""报告 <1>.pdf"",""size"":2048}","{""@t"":""file"",""name"":""报告 \u003c1\u003e.pdf"",""size"":2048,""url"":""https://example.com/f.pdf""}"
+any,"{""@t"":""web_page"",""url"":""https://example.com"",""title"":""Example"",""image"":{""url"":""https://example.com/i.png""}}","{""@t"":""web_page"",""image"":{""url"":""https://example.com/i.png""},""title"":""Example"",""url"":""https://example.com""}"
+any,"{""@t"":""string"",""@v"":""hello, 世界""}","{""@t"":""string"",""@v"":""hello, 世界""}"
+any,"{""@t"":""float64"",""@v"":3.25}","{""@t"":""float64"",""@v"":3.25}"
+cursor,0,AQAAAAAAAAAAAAAAAAAAAAAuOlPCEETFpfDwZX8-LKOp
+cursor,1,AQAAAAAAAAAAAAAAAAAAAAFH4JnShRGJdwbDSSZSzVz_
+cursor,163840382742659072,AQAAAAAAAAAAAkYT9cU9gADnOUAno7wQv5AEG4HKryxA
+cursor,9223372036854775807,AQAAAAAAAAAAf_________9rQdgHUkM6Qd7c1wx8NmdP
+cursor_invalid,,
+cursor_invalid,not a token,
+cursor_invalid,AQAAAAAAAAAAAAAAAAAABAFH4JnShRGJdwbDSSZSzVz_,
+cursor_invalid,AQAAAAAAAAAAAAAAAAAAAAFjyAyz9Navsyb7-qJTYLaD,
+cursor_invalid,AQAAAAAAAAAAAQID6ZsByZ3Pv4XV96kGmV22Zw,
+shard,8 8 0,0 0 0
+shard,8 8 65535,0 255 255
+shard,8 8 163840382742659072,2500005840189 128 0
+shard,0 12 1099511627853,268435456 0 77
+shard,4 10 987654321987,60281635 13 835
+shard,8 10 9007199254740991,34359738367 255 1023
+`
@@ -2,21 +2,33 @@ package gox
 
 import "sync/atomic"
 
+// cacheLineSize is the common cache line size of amd64 and arm64
+const cacheLineSize = 64
+
+// Counter is a lock-free counter. It's padded to a cache line to avoid false sharing with adjacent data.
 type Counter struct {
+	// count is the first field to be 64-bit aligned for atomic operations on 32-bit platforms
 	count int64
+	_     [cacheLineSize - 8]byte
 }
 
+// Next increments the counter and returns the new value
 func (c *Counter) Next() int64 {
-	atomic.AddInt64(&c.count, 1)
-	return c.count
+	return atomic.AddInt64(&c.count, 1)
 }
 
 func (c *Counter) GetNumber() int64 {
 	return c.Next()
 }
 
-var defaultCounter = &Counter{}
+// Count returns the current value
+func (c *Counter) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+// DefaultCounter is used by NextSequence
+var DefaultCounter = &Counter{}
 
 func NextSequence() int64 {
-	return defaultCounter.Next()
+	return DefaultCounter.Next()
 }
@@ -0,0 +1,77 @@
+package gox
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounter_Next(t *testing.T) {
+	c := new(Counter)
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				n := c.Next()
+				mu.Lock()
+				seen[n] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 8000 || c.Count() != 8000 {
+		t.Fatal(len(seen), c.Count())
+	}
+}
+
+type mutexCounter struct {
+	mu    sync.Mutex
+	count int64
+}
+
+func (c *mutexCounter) GetNumber() int64 {
+	c.mu.Lock()
+	c.count++
+	n := c.count
+	c.mu.Unlock()
+	return n
+}
+
+func BenchmarkCounterParallel(b *testing.B) {
+	b.Run("Atomic", func(b *testing.B) {
+		benchmarkNumberGetter(b, new(Counter))
+	})
+	b.Run("Mutex", func(b *testing.B) {
+		benchmarkNumberGetter(b, new(mutexCounter))
+	})
+}
+
+func benchmarkNumberGetter(b *testing.B, g NumberGetter) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.GetNumber()
+		}
+	})
+}
+
+func BenchmarkNextIDParallel(b *testing.B) {
+	b.Run("Atomic", func(b *testing.B) {
+		benchmarkNextID(b, new(Counter))
+	})
+	b.Run("Mutex", func(b *testing.B) {
+		benchmarkNextID(b, new(mutexCounter))
+	})
+}
+
+func benchmarkNextID(b *testing.B, seq NumberGetter) {
+	g := NewSnakeIDGenerator(0, 16, nil, nil, seq)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.NextID()
+		}
+	})
+}
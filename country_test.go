@@ -0,0 +1,36 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountryCode(t *testing.T) {
+	c, err := gox.ParseCountryCode("us")
+	require.NoError(t, err)
+	assert.Equal(t, gox.CountryCode("US"), c)
+	assert.Equal(t, "United States of America", c.Name())
+	assert.Equal(t, "🇺🇸", c.Flag())
+
+	_, err = gox.ParseCountryCode("XX")
+	assert.Error(t, err)
+
+	var v struct {
+		Country gox.CountryCode `json:"country"`
+		Region  gox.RegionCode  `json:"region"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"country":"jp","region":"us-ca"}`), &v))
+	assert.Equal(t, gox.CountryCode("JP"), v.Country)
+	assert.Equal(t, gox.RegionCode("US-CA"), v.Region)
+	assert.Equal(t, "CA", v.Region.Subdivision())
+	assert.Error(t, json.Unmarshal([]byte(`{"country":"ZZ"}`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{"region":"US"}`), &v))
+
+	var r gox.RegionCode
+	require.NoError(t, r.Scan([]byte("CN-11")))
+	assert.Equal(t, gox.CountryCode("CN"), r.Country())
+}
@@ -3,12 +3,24 @@ package gox
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"hash"
+	"io"
+	"os"
+
 	"github.com/gopub/log"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
 )
 
 // AES crypt data with key, iv
@@ -34,6 +46,99 @@ func AES(data []byte, key []byte, iv []byte) error {
 	return nil
 }
 
+// Encrypt encrypts plaintext with AES-256-GCM. key must be 32 bytes.
+// The random nonce is prepended to the result, which is required by Decrypt.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	randRead(nonce)
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts ciphertext returned by Encrypt with key
+func Decrypt(key, ciphertext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	n := aead.NonceSize()
+	if len(ciphertext) < n+aead.Overhead() {
+		return nil, errors.New("invalid ciphertext")
+	}
+	return aead.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+}
+
+// EncryptString encrypts str with Encrypt, and returns the result in url-safe base64 without padding
+func EncryptString(key []byte, str string) (string, error) {
+	b, err := Encrypt(key, []byte(str))
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecryptString decrypts str returned by EncryptString with key
+func DecryptString(key []byte, str string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return "", err
+	}
+
+	b, err = Decrypt(key, b)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("invalid key")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// DefaultPBKDF2Iterations is the iteration count of PBKDF2-HMAC-SHA256 recommended by OWASP
+const DefaultPBKDF2Iterations = 600000
+
+// PBKDF2Key derives a key from password with PBKDF2-HMAC-SHA256, e.g. an AES key for Encrypt from a passphrase.
+// iter defaults to DefaultPBKDF2Iterations and keyLen defaults to 32 if they aren't positive.
+// salt should be random and at least 16 bytes, and stored along with the encrypted data.
+func PBKDF2Key(password, salt []byte, iter, keyLen int) []byte {
+	if iter <= 0 {
+		iter = DefaultPBKDF2Iterations
+	}
+
+	if keyLen <= 0 {
+		keyLen = 32
+	}
+	return pbkdf2.Key(password, salt, iter, keyLen, sha256.New)
+}
+
+// HKDF derives n bytes from a high-entropy secret with HKDF-SHA256, e.g. per-purpose keys from a master key.
+// Different info results in independent keys. salt is optional. n should be in [1, 8160].
+func HKDF(secret, salt, info []byte, n int) []byte {
+	if n <= 0 || n > 255*sha256.Size {
+		panic("n should be in [1, 8160]")
+	}
+
+	key := make([]byte, n)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
 // XOR xor data with key
 func XOR(data []byte, key []byte) {
 	n := len(key)
@@ -81,3 +186,136 @@ func SHA256(str string) string {
 	}
 	return hex.EncodeToString(sha256er.Sum(nil))
 }
+
+// MD5Reader returns md5 value of all data read from r represented as 32 hex string
+func MD5Reader(r io.Reader) (string, error) {
+	return hashReader(md5.New(), r)
+}
+
+// SHA1Reader returns sha1 value of all data read from r represented as 40 hex string
+func SHA1Reader(r io.Reader) (string, error) {
+	return hashReader(sha1.New(), r)
+}
+
+// SHA256Reader returns sha256 value of all data read from r represented as 64 hex string.
+// Data is hashed in chunks, so it's suitable for large content such as uploads.
+func SHA256Reader(r io.Reader) (string, error) {
+	return hashReader(sha256.New(), r)
+}
+
+// MD5File returns md5 value of file content represented as 32 hex string
+func MD5File(filename string) (string, error) {
+	return hashFile(md5.New(), filename)
+}
+
+// SHA1File returns sha1 value of file content represented as 40 hex string
+func SHA1File(filename string) (string, error) {
+	return hashFile(sha1.New(), filename)
+}
+
+// SHA256File returns sha256 value of file content represented as 64 hex string
+func SHA256File(filename string) (string, error) {
+	return hashFile(sha256.New(), filename)
+}
+
+func hashReader(h hash.Hash, r io.Reader) (string, error) {
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h hash.Hash, filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return hashReader(h, f)
+}
+
+// SHA512 returns str's sha512 value which is 512 bits represented as 128 hex string
+func SHA512(str string) string {
+	return hex.EncodeToString(SHA512Bytes([]byte(str)))
+}
+
+// SHA512Bytes returns sha512 value of b
+func SHA512Bytes(b []byte) []byte {
+	sum := sha512.Sum512(b)
+	return sum[:]
+}
+
+// SHA3256 returns str's SHA3-256 value which is 256 bits represented as 64 hex string
+func SHA3256(str string) string {
+	return hex.EncodeToString(SHA3256Bytes([]byte(str)))
+}
+
+// SHA3256Bytes returns SHA3-256 value of b
+func SHA3256Bytes(b []byte) []byte {
+	sum := sha3.Sum256(b)
+	return sum[:]
+}
+
+// BLAKE2b returns str's BLAKE2b-256 value which is 256 bits represented as 64 hex string
+func BLAKE2b(str string) string {
+	return hex.EncodeToString(BLAKE2bBytes([]byte(str)))
+}
+
+// BLAKE2bBytes returns BLAKE2b-256 value of b
+func BLAKE2bBytes(b []byte) []byte {
+	sum := blake2b.Sum256(b)
+	return sum[:]
+}
+
+// HMACSHA256Bytes returns HMAC-SHA256 of msg with key
+func HMACSHA256Bytes(key, msg []byte) []byte {
+	return hmacSum(sha256.New, key, msg)
+}
+
+// HMACSHA1Bytes returns HMAC-SHA1 of msg with key
+func HMACSHA1Bytes(key, msg []byte) []byte {
+	return hmacSum(sha1.New, key, msg)
+}
+
+// HMACSHA256 returns HMAC-SHA256 of msg with key represented as 64 hex string
+func HMACSHA256(key, msg string) string {
+	return hex.EncodeToString(HMACSHA256Bytes([]byte(key), []byte(msg)))
+}
+
+// HMACSHA1 returns HMAC-SHA1 of msg with key represented as 40 hex string
+func HMACSHA1(key, msg string) string {
+	return hex.EncodeToString(HMACSHA1Bytes([]byte(key), []byte(msg)))
+}
+
+// HMACSHA256Base64 returns HMAC-SHA256 of msg with key in url-safe base64 without padding
+func HMACSHA256Base64(key, msg string) string {
+	return base64.RawURLEncoding.EncodeToString(HMACSHA256Bytes([]byte(key), []byte(msg)))
+}
+
+// HMACSHA1Base64 returns HMAC-SHA1 of msg with key in url-safe base64 without padding
+func HMACSHA1Base64(key, msg string) string {
+	return base64.RawURLEncoding.EncodeToString(HMACSHA1Bytes([]byte(key), []byte(msg)))
+}
+
+// VerifyHMAC reports whether mac is the HMAC of msg with key, e.g. VerifyHMAC(sha256.New, secret, body, signature).
+// It compares in constant time to avoid leaking timing information.
+func VerifyHMAC(h func() hash.Hash, key, msg, mac []byte) bool {
+	return SecureCompareBytes(mac, hmacSum(h, key, msg))
+}
+
+// SecureCompare reports whether a equals b in constant time, which should be used to compare secrets such as tokens and signatures.
+// It only leaks whether the lengths are equal.
+func SecureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// SecureCompareBytes reports whether a equals b in constant time
+func SecureCompareBytes(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+func hmacSum(h func() hash.Hash, key, msg []byte) []byte {
+	m := hmac.New(h, key)
+	m.Write(msg)
+	return m.Sum(nil)
+}
@@ -0,0 +1,105 @@
+package gox_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+const quickFox = "The quick brown fox jumps over the lazy dog"
+
+func TestHMAC(t *testing.T) {
+	assert.Equal(t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", gox.HMACSHA256("key", quickFox))
+	assert.Equal(t, "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9", gox.HMACSHA1("key", quickFox))
+	assert.Equal(t, "97yD9DBThCSxMpjmqm-xQ-9NWaFJRhdZl0edvC0aPNg", gox.HMACSHA256Base64("key", quickFox))
+
+	mac, _ := hex.DecodeString("f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")
+	assert.True(t, gox.VerifyHMAC(sha256.New, []byte("key"), []byte(quickFox), mac))
+	assert.False(t, gox.VerifyHMAC(sha256.New, []byte("key2"), []byte(quickFox), mac))
+}
+
+func TestHashes(t *testing.T) {
+	assert.Equal(t, "07e547d9586f6a73f73fbac0435ed76951218fb7d0c8d788a309d785436bbb642e93a252a954f23912547d1e8a3b5ed6e1bfd7097821233fa0538f3db854fee6", gox.SHA512(quickFox))
+	assert.Equal(t, "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532", gox.SHA3256("abc"))
+	assert.Equal(t, "0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8", gox.BLAKE2b(""))
+	assert.Len(t, gox.BLAKE2bBytes([]byte(quickFox)), 32)
+}
+
+func TestEncrypt(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	c1, err := gox.Encrypt(key, []byte(quickFox))
+	assert.NoError(t, err)
+	c2, err := gox.Encrypt(key, []byte(quickFox))
+	assert.NoError(t, err)
+	assert.NotEqual(t, c1, c2)
+
+	p, err := gox.Decrypt(key, c1)
+	assert.NoError(t, err)
+	assert.Equal(t, quickFox, string(p))
+
+	c1[len(c1)-1] ^= 1
+	_, err = gox.Decrypt(key, c1)
+	assert.Error(t, err)
+
+	_, err = gox.Encrypt(key[:16], []byte(quickFox))
+	assert.Error(t, err)
+
+	s, err := gox.EncryptString(key, quickFox)
+	assert.NoError(t, err)
+	d, err := gox.DecryptString(key, s)
+	assert.NoError(t, err)
+	assert.Equal(t, quickFox, d)
+
+	_, err = gox.DecryptString(key, "abc")
+	assert.Error(t, err)
+}
+
+func TestHashReader(t *testing.T) {
+	s, err := gox.SHA256Reader(strings.NewReader(quickFox))
+	assert.NoError(t, err)
+	assert.Equal(t, gox.SHA256(quickFox), s)
+	s, err = gox.MD5Reader(strings.NewReader(quickFox))
+	assert.NoError(t, err)
+	assert.Equal(t, gox.MD5(quickFox), s)
+
+	f, err := ioutil.TempFile("", "gox")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString(quickFox)
+	f.Close()
+	s, err = gox.SHA1File(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, gox.SHA1(quickFox), s)
+	s, err = gox.SHA256File(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, gox.SHA256(quickFox), s)
+
+	_, err = gox.SHA256File(f.Name() + ".missing")
+	assert.Error(t, err)
+}
+
+func TestSecureCompare(t *testing.T) {
+	assert.True(t, gox.SecureCompare("token", "token"))
+	assert.False(t, gox.SecureCompare("token", "Token"))
+	assert.False(t, gox.SecureCompare("token", "token1"))
+	assert.True(t, gox.SecureCompare("", ""))
+}
+
+func TestKeyDerivation(t *testing.T) {
+	// RFC 7914 section 11
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc", hex.EncodeToString(gox.PBKDF2Key([]byte("passwd"), []byte("salt"), 1, 32)))
+	assert.Len(t, gox.PBKDF2Key([]byte("passwd"), []byte("salt"), 1, 0), 32)
+
+	// RFC 5869 test case 1
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	assert.Equal(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865", hex.EncodeToString(gox.HKDF(ikm, salt, info, 42)))
+	assert.Panics(t, func() { gox.HKDF(ikm, nil, nil, 0) })
+}
@@ -0,0 +1,95 @@
+package gox
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"time"
+)
+
+const (
+	ErrInvalidCursor  ErrorString = "invalid cursor"
+	ErrCursorTampered ErrorString = "cursor tampered"
+	ErrCursorExpired  ErrorString = "cursor expired"
+)
+
+const (
+	cursorVersion = 1
+	cursorMACSize = 16
+)
+
+// CursorCodec encodes pagination positions into opaque tokens which can be exposed to untrusted clients.
+// Token layout is version(1) | expiry in unix seconds(8) | payload | truncated HMAC-SHA256(16), encoded in url-safe base64.
+type CursorCodec struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewCursorCodec creates a codec signing tokens with key. Tokens expire after ttl, or never if ttl is zero.
+func NewCursorCodec(key []byte, ttl time.Duration) *CursorCodec {
+	if len(key) < 16 {
+		panic("key should be at least 16 bytes")
+	}
+	return &CursorCodec{
+		key: append([]byte(nil), key...),
+		ttl: ttl,
+	}
+}
+
+// Encode returns token of keyset position after, i.e. the last ID of current page
+func (c *CursorCodec) Encode(after ID) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(after))
+	return c.Sign(b[:])
+}
+
+// Decode returns ID encoded by Encode
+func (c *CursorCodec) Decode(token string) (ID, error) {
+	payload, err := c.Verify(token)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(payload) != 8 {
+		return 0, ErrInvalidCursor
+	}
+	return ID(binary.BigEndian.Uint64(payload)), nil
+}
+
+// Sign returns token of arbitrary payload, e.g. a composite key of sort value and ID
+func (c *CursorCodec) Sign(payload []byte) string {
+	b := make([]byte, 9, 9+len(payload)+cursorMACSize)
+	b[0] = cursorVersion
+	if c.ttl != 0 {
+		binary.BigEndian.PutUint64(b[1:9], uint64(time.Now().Add(c.ttl).Unix()))
+	}
+	b = append(b, payload...)
+	b = append(b, c.mac(b)...)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Verify returns payload of token.
+// It returns ErrInvalidCursor if token is malformed, ErrCursorTampered if signature mismatches and ErrCursorExpired if token is expired.
+func (c *CursorCodec) Verify(token string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) < 9+cursorMACSize || b[0] != cursorVersion {
+		return nil, ErrInvalidCursor
+	}
+
+	data, mac := b[:len(b)-cursorMACSize], b[len(b)-cursorMACSize:]
+	if !SecureCompareBytes(mac, c.mac(data)) {
+		return nil, ErrCursorTampered
+	}
+
+	if expiresAt := int64(binary.BigEndian.Uint64(data[1:9])); expiresAt > 0 && time.Now().Unix() >= expiresAt {
+		return nil, ErrCursorExpired
+	}
+	return data[9:], nil
+}
+
+func (c *CursorCodec) mac(data []byte) []byte {
+	h := hmac.New(sha256.New, c.key)
+	h.Write(data)
+	return h.Sum(nil)[:cursorMACSize]
+}
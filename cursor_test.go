@@ -0,0 +1,36 @@
+package gox_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorCodec(t *testing.T) {
+	c := gox.NewCursorCodec([]byte("0123456789abcdef"), time.Hour)
+	token := c.Encode(123456789)
+	id, err := c.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, gox.ID(123456789), id)
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	b[10] ^= 1
+	_, err = c.Decode(base64.RawURLEncoding.EncodeToString(b))
+	assert.Equal(t, gox.ErrCursorTampered, err)
+
+	_, err = c.Decode("abc")
+	assert.Equal(t, gox.ErrInvalidCursor, err)
+
+	other := gox.NewCursorCodec([]byte("fedcba9876543210"), time.Hour)
+	_, err = other.Decode(token)
+	assert.Equal(t, gox.ErrCursorTampered, err)
+
+	expired := gox.NewCursorCodec([]byte("0123456789abcdef"), -time.Second)
+	_, err = expired.Decode(expired.Encode(1))
+	assert.Equal(t, gox.ErrCursorExpired, err)
+}
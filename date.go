@@ -0,0 +1,157 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DateLayout is the text form of Date
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date without time and timezone, e.g. a birthday.
+// Zero value means no date, which is encoded as null in JSON and database.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+var _ driver.Valuer = Date{}
+var _ sql.Scanner = (*Date)(nil)
+
+// NewDate returns the date normalized like time.Date, e.g. NewDate(2020, 2, 30) is 2020-03-01
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of t in t's location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// Today returns the current date in tz, which defaults to time.Local
+func Today(tz *time.Location) Date {
+	if tz == nil {
+		tz = time.Local
+	}
+	return DateOf(DefaultClock().Now().In(tz))
+}
+
+// ParseDate parses s in DateLayout, e.g. 2006-01-02
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+// DaysBetween returns number of days from a to b, which is negative if b is before a
+func DaysBetween(a, b Date) int {
+	return int(b.Time(time.UTC).Sub(a.Time(time.UTC)) / (24 * time.Hour))
+}
+
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// Time returns midnight of d in loc
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, d.Month, d.Day+n)
+}
+
+func (d Date) Weekday() time.Weekday {
+	return d.Time(time.UTC).Weekday()
+}
+
+func (d Date) Before(o Date) bool {
+	return DaysBetween(d, o) > 0
+}
+
+func (d Date) After(o Date) bool {
+	return DaysBetween(d, o) < 0
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON accepts a string in DateLayout. null and "" are decoded as zero value.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	s, err := strconv.Unquote(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Date: %v", b, err)
+	}
+
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+
+	v, err := ParseDate(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Date: %v", s, err)
+	}
+	*d = v
+	return nil
+}
+
+// Scan accepts time.Time of DATE columns, and strings such as 2006-01-02 and 2006-01-02 15:04:05
+func (d *Date) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.Date", src)
+	}
+
+	// drop time part of DATETIME text
+	if len(s) > len(DateLayout) && (s[len(DateLayout)] == ' ' || s[len(DateLayout)] == 'T') {
+		s = s[:len(DateLayout)]
+	}
+
+	v, err := ParseDate(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Date: %v", s, err)
+	}
+	*d = v
+	return nil
+}
+
+// Value returns d in DateLayout, which isn't shifted by timezone of database connections
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
@@ -0,0 +1,75 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate(t *testing.T) {
+	d := gox.NewDate(2020, time.February, 28)
+	assert.Equal(t, "2020-02-29", d.AddDays(1).String())
+	assert.Equal(t, "2020-03-01", d.AddDays(2).String())
+	assert.Equal(t, "2019-12-31", gox.NewDate(2020, time.January, 1).AddDays(-1).String())
+	assert.Equal(t, 366, gox.DaysBetween(gox.NewDate(2020, 1, 1), gox.NewDate(2021, 1, 1)))
+	assert.Equal(t, -2, gox.DaysBetween(d.AddDays(2), d))
+	assert.True(t, d.Before(d.AddDays(1)))
+	assert.True(t, d.AddDays(1).After(d))
+	assert.Equal(t, time.Friday, d.Weekday())
+
+	_, err := gox.ParseDate("2020-02-30")
+	assert.Error(t, err)
+}
+
+func TestToday(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 20, 0, 0, 0, time.UTC)
+	gox.SetDefaultClock(gox.NewMockClock(now))
+	defer gox.SetDefaultClock(gox.LocalClock())
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	assert.Equal(t, gox.NewDate(2020, time.January, 1), gox.Today(time.UTC))
+	assert.Equal(t, gox.NewDate(2020, time.January, 2), gox.Today(shanghai))
+}
+
+func TestDate_JSON(t *testing.T) {
+	var v struct {
+		Birthday gox.Date  `json:"birthday"`
+		Expiry   *gox.Date `json:"expiry"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"birthday":"1990-05-17","expiry":null}`), &v))
+	assert.Equal(t, gox.NewDate(1990, time.May, 17), v.Birthday)
+	assert.Nil(t, v.Expiry)
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"birthday":"1990-05-17","expiry":null}`, string(b))
+
+	var d gox.Date
+	assert.Error(t, json.Unmarshal([]byte(`"1990/05/17"`), &d))
+	assert.Error(t, json.Unmarshal([]byte(`19900517`), &d))
+}
+
+func TestDate_SQL(t *testing.T) {
+	d := gox.NewDate(1990, time.May, 17)
+	val, err := d.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "1990-05-17", val)
+
+	var v gox.Date
+	require.NoError(t, v.Scan([]byte("1990-05-17")))
+	assert.Equal(t, d, v)
+	require.NoError(t, v.Scan("1990-05-17 00:00:00"))
+	assert.Equal(t, d, v)
+	require.NoError(t, v.Scan(time.Date(1990, time.May, 17, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, d, v)
+	require.NoError(t, v.Scan(nil))
+	assert.True(t, v.IsZero())
+
+	val, err = v.Value()
+	require.NoError(t, err)
+	assert.Nil(t, val)
+}
@@ -0,0 +1,334 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	MustRegisterAny(&Decimal{})
+}
+
+// RoundingMode decides how Decimal drops digits
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds half away from zero, e.g. 2.5 to 3, -2.5 to -3
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds half to the even neighbor, e.g. 2.5 to 2, 3.5 to 4
+	RoundHalfEven
+	// RoundDown truncates toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+)
+
+// maxDecimalExp limits exponent of parsed text, e.g. 1e10000, to avoid huge allocations
+const maxDecimalExp = 10000
+
+// Decimal is an exact decimal number: unscaled * 10^-scale. Zero value is 0.
+// It's immutable, and operations return new values.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+var _ driver.Valuer = Decimal{}
+var _ sql.Scanner = (*Decimal)(nil)
+var _ json.Marshaler = Decimal{}
+var _ json.Unmarshaler = (*Decimal)(nil)
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1234, 2) is 12.34
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		panic("scale is negative")
+	}
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parses s such as "-12.340" and "1.5e3". Trailing zeros are kept in scale.
+func ParseDecimal(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	mantissa, exp := text, 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, err := strconv.Atoi(text[i+1:])
+		if err != nil || e > maxDecimalExp || e < -maxDecimalExp {
+			return Decimal{}, fmt.Errorf("failed to parse %s into gox.Decimal: invalid exponent", s)
+		}
+		mantissa, exp = text[:i], e
+	}
+
+	sign := ""
+	if len(mantissa) > 0 && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+
+	digits := intPart + fracPart
+	if len(digits) == 0 {
+		return Decimal{}, fmt.Errorf("failed to parse %s into gox.Decimal", s)
+	}
+
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(digits[i]) {
+			return Decimal{}, fmt.Errorf("failed to parse %s into gox.Decimal: %v", s, ErrInvalidChar)
+		}
+	}
+
+	v, _ := new(big.Int).SetString(sign+digits, 10)
+	scale := len(fracPart) - exp
+	if scale < 0 {
+		v.Mul(v, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: v, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is invalid
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1, 0 or +1 if d is less than, equal to or greater than o. Scale is ignored, e.g. 1.50 equals 1.5.
+func (d Decimal) Cmp(o Decimal) int {
+	x, y, _ := alignDecimals(d, o)
+	return x.Cmp(y)
+}
+
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Add returns d+o with the larger scale of them
+func (d Decimal) Add(o Decimal) Decimal {
+	x, y, scale := alignDecimals(d, o)
+	return Decimal{unscaled: x.Add(x, y), scale: scale}
+}
+
+// Sub returns d-o with the larger scale of them
+func (d Decimal) Sub(o Decimal) Decimal {
+	x, y, scale := alignDecimals(d, o)
+	return Decimal{unscaled: x.Sub(x, y), scale: scale}
+}
+
+// Mul returns d*o with scale of d.Scale()+o.Scale()
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// Div returns d/o with scale digits after the decimal point rounded by mode. It panics if o is 0.
+func (d Decimal) Div(o Decimal, scale int32, mode RoundingMode) Decimal {
+	if o.IsZero() {
+		panic("division by zero")
+	}
+
+	if scale < 0 {
+		panic("scale is negative")
+	}
+
+	// d/o = (du*10^(scale+os)) / (ou*10^ds) * 10^-scale
+	n := new(big.Int).Mul(d.int(), pow10(int(scale+o.scale)))
+	m := new(big.Int).Mul(o.int(), pow10(int(d.scale)))
+	return Decimal{unscaled: roundQuo(n, m, mode), scale: scale}
+}
+
+// Round returns d with scale digits after the decimal point rounded by mode.
+// Zeros are appended if scale is larger than d.Scale().
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale < 0 {
+		panic("scale is negative")
+	}
+
+	if scale >= d.scale {
+		v := new(big.Int).Mul(d.int(), pow10(int(scale-d.scale)))
+		return Decimal{unscaled: v, scale: scale}
+	}
+	return Decimal{unscaled: roundQuo(d.int(), pow10(int(d.scale-scale)), mode), scale: scale}
+}
+
+// Float64 returns the nearest float64 of d, which may lose precision
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain notation with trailing zeros in scale, e.g. -12.340
+func (d Decimal) String() string {
+	v := d.int()
+	digits := new(big.Int).Abs(v).String()
+	if d.scale > 0 {
+		if n := int(d.scale) + 1 - len(digits); n > 0 {
+			digits = strings.Repeat("0", n) + digits
+		}
+		i := len(digits) - int(d.scale)
+		digits = digits[:i] + "." + digits[i:]
+	}
+
+	if v.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON encodes d as a string to keep precision in clients such as javascript
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON accepts a string or a number, which is parsed from text without converting to float64
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	if len(s) > 0 && s[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d *Decimal) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.Decimal", src)
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value returns d as a string, which can be stored in DECIMAL/NUMERIC columns without losing precision
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// alignDecimals returns copies of unscaled values of a and b in the larger scale of them
+func alignDecimals(a, b Decimal) (x, y *big.Int, scale int32) {
+	x, y = new(big.Int).Set(a.int()), new(big.Int).Set(b.int())
+	switch {
+	case a.scale < b.scale:
+		x.Mul(x, pow10(int(b.scale-a.scale)))
+		return x, y, b.scale
+	case a.scale > b.scale:
+		y.Mul(y, pow10(int(a.scale-b.scale)))
+	}
+	return x, y, a.scale
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundQuo returns n/m rounded by mode
+func roundQuo(n, m *big.Int, mode RoundingMode) *big.Int {
+	n, m = new(big.Int).Set(n), new(big.Int).Set(m)
+	if m.Sign() < 0 {
+		n.Neg(n)
+		m.Neg(m)
+	}
+
+	q, r := new(big.Int).QuoRem(n, m, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	neg := n.Sign() < 0
+	half := r.Abs(r).Lsh(r, 1).Cmp(m)
+	var inc bool
+	switch mode {
+	case RoundHalfUp:
+		inc = half >= 0
+	case RoundHalfEven:
+		inc = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case RoundDown:
+		inc = false
+	case RoundUp:
+		inc = true
+	case RoundFloor:
+		inc = neg
+	case RoundCeiling:
+		inc = !neg
+	default:
+		panic(fmt.Sprintf("invalid rounding mode %d", mode))
+	}
+
+	if inc {
+		if neg {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
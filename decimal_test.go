@@ -0,0 +1,107 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := map[string]string{
+		"0":                                  "0",
+		"12.340":                             "12.340",
+		"-0.05":                              "-0.05",
+		"+.5":                                "0.5",
+		"1.5e3":                              "1500",
+		"1.5E-3":                             "0.0015",
+		"007":                                "7",
+		"123456789012345678901234567890.123": "123456789012345678901234567890.123",
+	}
+	for s, expected := range tests {
+		d, err := gox.ParseDecimal(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, d.String(), s)
+	}
+
+	for _, s := range []string{"", "-", ".", "1.2.3", "1a", "1e", "1e99999"} {
+		_, err := gox.ParseDecimal(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := gox.MustParseDecimal("0.1")
+	b := gox.MustParseDecimal("0.20")
+	assert.Equal(t, "0.30", a.Add(b).String())
+	assert.Equal(t, "-0.10", a.Sub(b).String())
+	assert.Equal(t, "0.020", a.Mul(b).String())
+	assert.Equal(t, "0.3333", a.Div(gox.NewDecimal(3, 1), 4, gox.RoundHalfUp).String())
+	assert.True(t, a.Add(b).Equal(gox.MustParseDecimal("0.3")))
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 1, b.Cmp(gox.Decimal{}))
+	assert.True(t, gox.Decimal{}.IsZero())
+	assert.Equal(t, "0", gox.Decimal{}.String())
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		mode     gox.RoundingMode
+		values   []string
+		expected []string
+	}{
+		{gox.RoundHalfUp, []string{"2.5", "-2.5", "2.49"}, []string{"3", "-3", "2"}},
+		{gox.RoundHalfEven, []string{"2.5", "3.5", "-2.5", "2.51"}, []string{"2", "4", "-2", "3"}},
+		{gox.RoundDown, []string{"2.9", "-2.9"}, []string{"2", "-2"}},
+		{gox.RoundUp, []string{"2.1", "-2.1", "2.0"}, []string{"3", "-3", "2"}},
+		{gox.RoundFloor, []string{"2.9", "-2.1"}, []string{"2", "-3"}},
+		{gox.RoundCeiling, []string{"2.1", "-2.9"}, []string{"3", "-2"}},
+	}
+	for _, test := range tests {
+		for i, s := range test.values {
+			assert.Equal(t, test.expected[i], gox.MustParseDecimal(s).Round(0, test.mode).String(), s)
+		}
+	}
+	assert.Equal(t, "1.2300", gox.MustParseDecimal("1.23").Round(4, gox.RoundDown).String())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		Price gox.Decimal `json:"price"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":0.1000000000000000055511}`), &v))
+	assert.Equal(t, "0.1000000000000000055511", v.Price.String())
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"price":"0.1000000000000000055511"}`, string(b))
+
+	require.NoError(t, json.Unmarshal(b, &v))
+	assert.Equal(t, "0.1000000000000000055511", v.Price.String())
+
+	a := gox.NewAny(gox.MustParseDecimal("19.99"))
+	b, err = json.Marshal(a)
+	require.NoError(t, err)
+	var a2 gox.Any
+	require.NoError(t, json.Unmarshal(b, &a2))
+	d, ok := a2.Val().(*gox.Decimal)
+	require.True(t, ok, string(b))
+	assert.Equal(t, "19.99", d.String())
+}
+
+func TestDecimal_SQL(t *testing.T) {
+	d := gox.MustParseDecimal("-12.340")
+	val, err := d.Value()
+	require.NoError(t, err)
+
+	var v gox.Decimal
+	require.NoError(t, v.Scan([]byte(val.(string))))
+	assert.Equal(t, "-12.340", v.String())
+	require.NoError(t, v.Scan(int64(5)))
+	assert.Equal(t, "5", v.String())
+	require.NoError(t, v.Scan(1.25))
+	assert.Equal(t, "1.25", v.String())
+	assert.Error(t, v.Scan(true))
+}
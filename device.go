@@ -0,0 +1,156 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	MustRegisterAny(&Device{})
+}
+
+// Device describes the client of a request
+type Device struct {
+	OS        string `json:"os,omitempty"`
+	OSVersion string `json:"os_version,omitempty"`
+	Browser   string `json:"browser,omitempty"`
+	Model     string `json:"model,omitempty"`
+	IsBot     bool   `json:"is_bot,omitempty"`
+}
+
+var _ driver.Valuer = (*Device)(nil)
+var _ sql.Scanner = (*Device)(nil)
+
+func (d *Device) String() string {
+	s := strings.TrimSpace(d.OS + " " + d.OSVersion)
+	if len(d.Model) > 0 {
+		s += " " + d.Model
+	}
+
+	if len(d.Browser) > 0 {
+		s += " " + d.Browser
+	}
+
+	if d.IsBot {
+		s += " bot"
+	}
+	return strings.TrimSpace(s)
+}
+
+func (d *Device) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.Device", src)
+	}
+}
+
+// Value returns JSON, which can be stored in a json or jsonb column
+func (d *Device) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+var (
+	botRegexp          = regexp.MustCompile(`(?i)bot\b|crawler|spider|slurp|curl/|wget/|python-requests|go-http-client|okhttp|facebookexternalhit|headlesschrome`)
+	windowsRegexp      = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	iosRegexp          = regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`)
+	macRegexp          = regexp.MustCompile(`Mac OS X ([\d_.]+)`)
+	androidRegexp      = regexp.MustCompile(`Android ([\d.]+)(?:; ([^;)]+))?`)
+	androidBuildRegexp = regexp.MustCompile(`\s*Build/.*$`)
+)
+
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// browserTokens are checked in order, as most browsers include tokens of the browsers they are based on
+var browserTokens = []struct {
+	token string
+	name  string
+}{
+	{"Edg/", "Edge"},
+	{"Edge/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Browser"},
+	{"MicroMessenger/", "WeChat"},
+	{"FxiOS/", "Firefox"},
+	{"Firefox/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"MSIE ", "Internet Explorer"},
+	{"Trident/", "Internet Explorer"},
+}
+
+// ParseUserAgent recognizes common operating systems, browsers, device models and bots in ua.
+// Unrecognized fields are left empty.
+func ParseUserAgent(ua string) *Device {
+	d := &Device{}
+	d.IsBot = botRegexp.MatchString(ua)
+
+	switch {
+	case strings.Contains(ua, "Windows"):
+		d.OS = "Windows"
+		if m := windowsRegexp.FindStringSubmatch(ua); m != nil {
+			d.OSVersion = windowsVersions[m[1]]
+		}
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		d.OS = "iOS"
+		for _, model := range []string{"iPhone", "iPad", "iPod"} {
+			if strings.Contains(ua, model) {
+				d.Model = model
+				break
+			}
+		}
+		if m := iosRegexp.FindStringSubmatch(ua); m != nil {
+			d.OSVersion = strings.Replace(m[1], "_", ".", -1)
+		}
+	case strings.Contains(ua, "Android"):
+		d.OS = "Android"
+		if m := androidRegexp.FindStringSubmatch(ua); m != nil {
+			d.OSVersion = m[1]
+			d.Model = strings.TrimSpace(androidBuildRegexp.ReplaceAllString(m[2], ""))
+			if d.Model == "K" {
+				// reduced user agent since Chrome 110 hides model
+				d.Model = ""
+			}
+		}
+	case strings.Contains(ua, "Macintosh"):
+		d.OS = "macOS"
+		d.Model = "Mac"
+		if m := macRegexp.FindStringSubmatch(ua); m != nil {
+			d.OSVersion = strings.Replace(m[1], "_", ".", -1)
+		}
+	case strings.Contains(ua, "CrOS"):
+		d.OS = "Chrome OS"
+	case strings.Contains(ua, "Linux"):
+		d.OS = "Linux"
+	}
+
+	for _, b := range browserTokens {
+		if strings.Contains(ua, b.token) {
+			d.Browser = b.name
+			break
+		}
+	}
+	return d
+}
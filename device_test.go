@@ -0,0 +1,55 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		UA     string
+		Device gox.Device
+	}{
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.5 Mobile/15E148 Safari/604.1",
+			gox.Device{OS: "iOS", OSVersion: "13.3", Model: "iPhone", Browser: "Safari"},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 10; SM-G975F Build/QP1A.190711.020) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.99 Mobile Safari/537.36",
+			gox.Device{OS: "Android", OSVersion: "10", Model: "SM-G975F", Browser: "Chrome"},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/80.0.3987.87 Safari/537.36 Edg/80.0.361.48",
+			gox.Device{OS: "Windows", OSVersion: "10", Browser: "Edge"},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:73.0) Gecko/20100101 Firefox/73.0",
+			gox.Device{OS: "macOS", OSVersion: "10.15", Model: "Mac", Browser: "Firefox"},
+		},
+		{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			gox.Device{IsBot: true},
+		},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.Device, *gox.ParseUserAgent(tc.UA), tc.UA)
+	}
+}
+
+func TestDevice_Scan(t *testing.T) {
+	d := gox.ParseUserAgent("curl/7.64.1")
+	assert.True(t, d.IsBot)
+	v, err := d.Value()
+	require.NoError(t, err)
+
+	var d2 gox.Device
+	require.NoError(t, d2.Scan(v))
+	assert.Equal(t, *d, d2)
+
+	a := gox.NewAny(d)
+	assert.Equal(t, "device", a.TypeName())
+}
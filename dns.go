@@ -0,0 +1,106 @@
+package gox
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLookupTimeout limits each dns query of LookupIPCached
+	DefaultLookupTimeout = 5 * time.Second
+
+	// maxDNSEntries triggers removal of expired entries
+	maxDNSEntries = 1024
+)
+
+type dnsEntry struct {
+	ips       []net.IP
+	expiresAt time.Time
+}
+
+type dnsCall struct {
+	done chan struct{}
+	ips  []net.IP
+	err  error
+}
+
+var dnsCache = struct {
+	mu      sync.Mutex
+	entries map[string]*dnsEntry
+	calls   map[string]*dnsCall
+}{
+	entries: make(map[string]*dnsEntry),
+	calls:   make(map[string]*dnsCall),
+}
+
+// lookupIPAddr is replaced in tests
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// LookupIPCached returns ip addresses of host, which are cached for ttl.
+// Concurrent lookups of the same host share one query. Failed lookups aren't cached.
+// The returned slice is shared and shouldn't be modified.
+func LookupIPCached(ctx context.Context, host string, ttl time.Duration) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	c := &dnsCache
+	c.mu.Lock()
+	now := time.Now()
+	if e, ok := c.entries[host]; ok {
+		if now.Before(e.expiresAt) {
+			c.mu.Unlock()
+			return e.ips, nil
+		}
+		delete(c.entries, host)
+	}
+
+	call, ok := c.calls[host]
+	if !ok {
+		call = &dnsCall{done: make(chan struct{})}
+		c.calls[host] = call
+		go resolveHost(host, ttl, call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.ips, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resolveHost isn't bound to the caller's context, so that canceling one caller doesn't fail others waiting for the same host
+func resolveHost(host string, ttl time.Duration, call *dnsCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultLookupTimeout)
+	addrs, err := lookupIPAddr(ctx, host)
+	cancel()
+
+	if err == nil {
+		call.ips = make([]net.IP, len(addrs))
+		for i, a := range addrs {
+			call.ips[i] = a.IP
+		}
+	}
+	call.err = err
+
+	c := &dnsCache
+	c.mu.Lock()
+	delete(c.calls, host)
+	if err == nil && ttl > 0 {
+		now := time.Now()
+		if len(c.entries) >= maxDNSEntries {
+			for h, e := range c.entries {
+				if !now.Before(e.expiresAt) {
+					delete(c.entries, h)
+				}
+			}
+		}
+		c.entries[host] = &dnsEntry{ips: call.ips, expiresAt: now.Add(ttl)}
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
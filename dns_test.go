@@ -0,0 +1,63 @@
+package gox
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLookupIPCached(t *testing.T) {
+	dnsCache.mu.Lock()
+	dnsCache.entries = make(map[string]*dnsEntry)
+	dnsCache.mu.Unlock()
+
+	var n int32
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		atomic.AddInt32(&n, 1)
+		time.Sleep(10 * time.Millisecond)
+		return []net.IPAddr{{IP: net.IPv4(10, 0, 0, 1)}}, nil
+	}
+	defer func() {
+		lookupIPAddr = net.DefaultResolver.LookupIPAddr
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ips, err := LookupIPCached(context.Background(), "db.internal", time.Minute)
+			if err != nil || len(ips) != 1 || !ips[0].Equal(net.IPv4(10, 0, 0, 1)) {
+				t.Error(ips, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := LookupIPCached(context.Background(), "db.internal", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if atomic.LoadInt32(&n) != 1 {
+		t.Fatal("expect 1 lookup, got", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := LookupIPCached(ctx, "cache.internal", time.Minute); err != context.Canceled {
+		t.Fatal(err)
+	}
+
+	// the query goes on for other callers
+	if _, err := LookupIPCached(context.Background(), "cache.internal", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	ips, err := LookupIPCached(context.Background(), "10.0.0.2", time.Minute)
+	if err != nil || !ips[0].Equal(net.IPv4(10, 0, 0, 2)) {
+		t.Fatal(ips, err)
+	}
+}
@@ -0,0 +1,70 @@
+package gox
+
+import "sync"
+
+// DuplicateDetector wraps an IDGenerator and reports ids which collide with recently generated ids.
+// It's intended for soak testing custom shard id and sequence getters, e.g. by sharing one detector
+// between generators of different shards.
+type DuplicateDetector struct {
+	gen         IDGenerator
+	onDuplicate func(id ID)
+
+	mu         sync.Mutex
+	seen       map[ID]struct{}
+	window     []ID
+	next       int
+	duplicates int64
+}
+
+var _ IDGenerator = (*DuplicateDetector)(nil)
+
+// NewDuplicateDetector creates a detector remembering the last windowSize ids. onDuplicate is called for every duplicate, and can be nil.
+func NewDuplicateDetector(g IDGenerator, windowSize int, onDuplicate func(id ID)) *DuplicateDetector {
+	if windowSize <= 0 {
+		panic("windowSize should be positive")
+	}
+	return &DuplicateDetector{
+		gen:         g,
+		onDuplicate: onDuplicate,
+		seen:        make(map[ID]struct{}, windowSize),
+		window:      make([]ID, 0, windowSize),
+	}
+}
+
+// NextID returns id generated by the wrapped generator after checking it
+func (d *DuplicateDetector) NextID() ID {
+	id := d.gen.NextID()
+	d.Check(id)
+	return id
+}
+
+// Check records id and reports whether it's in the window, which is useful for ids from other generators
+func (d *DuplicateDetector) Check(id ID) bool {
+	d.mu.Lock()
+	_, dup := d.seen[id]
+	if dup {
+		d.duplicates++
+	} else {
+		if len(d.window) < cap(d.window) {
+			d.window = append(d.window, id)
+		} else {
+			delete(d.seen, d.window[d.next])
+			d.window[d.next] = id
+			d.next = (d.next + 1) % len(d.window)
+		}
+		d.seen[id] = struct{}{}
+	}
+	d.mu.Unlock()
+
+	if dup && d.onDuplicate != nil {
+		d.onDuplicate(id)
+	}
+	return dup
+}
+
+// Duplicates returns the number of duplicates found
+func (d *DuplicateDetector) Duplicates() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.duplicates
+}
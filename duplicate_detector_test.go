@@ -0,0 +1,32 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+type idSequence []gox.ID
+
+func (s *idSequence) NextID() gox.ID {
+	id := (*s)[0]
+	*s = (*s)[1:]
+	return id
+}
+
+func TestDuplicateDetector(t *testing.T) {
+	var found []gox.ID
+	g := &idSequence{1, 2, 1, 3, 4, 1, 4}
+	d := gox.NewDuplicateDetector(g, 3, func(id gox.ID) {
+		found = append(found, id)
+	})
+	for i := 0; i < 7; i++ {
+		d.NextID()
+	}
+	// 1 is out of window when it's generated the third time
+	assert.Equal(t, []gox.ID{1, 4}, found)
+	assert.Equal(t, int64(2), d.Duplicates())
+	assert.True(t, d.Check(4))
+	assert.False(t, d.Check(5))
+}
@@ -0,0 +1,98 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Duration is time.Duration encoded as a string such as "1h30m0s" in JSON, and int64 nanoseconds in database
+type Duration time.Duration
+
+var _ driver.Valuer = Duration(0)
+var _ sql.Scanner = (*Duration)(nil)
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON accepts strings parsed by time.ParseDuration, e.g. "1h30m" and "90s", and integer milliseconds.
+// null is decoded as 0.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		*d = 0
+		return nil
+	}
+
+	if len(s) == 0 || s[0] != '"' {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s into gox.Duration: milliseconds should be an integer", s)
+		}
+
+		if max := int64(math.MaxInt64 / time.Millisecond); ms > max || ms < -max {
+			return fmt.Errorf("failed to parse %s into gox.Duration: %v", s, ErrOverflow)
+		}
+		*d = Duration(time.Duration(ms) * time.Millisecond)
+		return nil
+	}
+
+	s, err := strconv.Unquote(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Duration: %v", b, err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Duration: %v", s, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Scan accepts int64 nanoseconds, and text of nanoseconds or time.ParseDuration
+func (d *Duration) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*d = 0
+		return nil
+	case int64:
+		*d = Duration(v)
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.Duration", src)
+	}
+
+	if ns, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*d = Duration(ns)
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Duration: %v", s, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) Value() (driver.Value, error) {
+	return int64(d), nil
+}
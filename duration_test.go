@@ -0,0 +1,55 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration_JSON(t *testing.T) {
+	var v struct {
+		Timeout gox.Duration `json:"timeout"`
+	}
+	tests := map[string]time.Duration{
+		`"1h30m"`: 90 * time.Minute,
+		`"90s"`:   90 * time.Second,
+		`1500`:    1500 * time.Millisecond,
+		`-20`:     -20 * time.Millisecond,
+		`null`:    0,
+	}
+	for in, expected := range tests {
+		v.Timeout = 1
+		require.NoError(t, json.Unmarshal([]byte(`{"timeout":`+in+`}`), &v), in)
+		assert.Equal(t, expected, v.Timeout.Duration(), in)
+	}
+
+	v.Timeout = gox.Duration(90 * time.Minute)
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"timeout":"1h30m0s"}`, string(b))
+
+	var d gox.Duration
+	assert.Error(t, json.Unmarshal([]byte(`1.5`), &d))
+	assert.Error(t, json.Unmarshal([]byte(`"90"`), &d))
+	assert.Error(t, json.Unmarshal([]byte(`9223372036854775`), &d))
+}
+
+func TestDuration_SQL(t *testing.T) {
+	d := gox.Duration(90 * time.Second)
+	val, err := d.Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(90*time.Second), val)
+
+	var v gox.Duration
+	require.NoError(t, v.Scan(val))
+	assert.Equal(t, d, v)
+	require.NoError(t, v.Scan([]byte("90000000000")))
+	assert.Equal(t, d, v)
+	require.NoError(t, v.Scan("1m30s"))
+	assert.Equal(t, d, v)
+	assert.Error(t, v.Scan(1.5))
+}
@@ -0,0 +1,178 @@
+package gox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EdgeStore stores directed edges between IDs, e.g. follow relationships
+type EdgeStore interface {
+	// AddEdge adds edge from -> to. It's idempotent.
+	AddEdge(ctx context.Context, from, to ID) error
+
+	RemoveEdge(ctx context.Context, from, to ID) error
+
+	HasEdge(ctx context.Context, from, to ID) (bool, error)
+
+	// Neighbors returns at most limit IDs which id points to, in ascending order and greater than after
+	Neighbors(ctx context.Context, id, after ID, limit int) ([]ID, error)
+
+	// MutualCount returns the number of IDs which both a and b point to
+	MutualCount(ctx context.Context, a, b ID) (int, error)
+}
+
+type memoryEdgeStore struct {
+	mu    sync.RWMutex
+	edges map[ID]map[ID]time.Time
+}
+
+// NewMemoryEdgeStore returns an EdgeStore in memory, which is only suitable for a single instance or tests
+func NewMemoryEdgeStore() EdgeStore {
+	return &memoryEdgeStore{edges: make(map[ID]map[ID]time.Time)}
+}
+
+func (s *memoryEdgeStore) AddEdge(ctx context.Context, from, to ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.edges[from]
+	if m == nil {
+		m = make(map[ID]time.Time)
+		s.edges[from] = m
+	}
+
+	if _, ok := m[to]; !ok {
+		m[to] = time.Now()
+	}
+	return nil
+}
+
+func (s *memoryEdgeStore) RemoveEdge(ctx context.Context, from, to ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m := s.edges[from]; m != nil {
+		delete(m, to)
+		if len(m) == 0 {
+			delete(s.edges, from)
+		}
+	}
+	return nil
+}
+
+func (s *memoryEdgeStore) HasEdge(ctx context.Context, from, to ID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.edges[from][to]
+	return ok, nil
+}
+
+func (s *memoryEdgeStore) Neighbors(ctx context.Context, id, after ID, limit int) ([]ID, error) {
+	s.mu.RLock()
+	ids := make([]ID, 0, len(s.edges[id]))
+	for to := range s.edges[id] {
+		if to > after {
+			ids = append(ids, to)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (s *memoryEdgeStore) MutualCount(ctx context.Context, a, b ID) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ma, mb := s.edges[a], s.edges[b]
+	if len(ma) > len(mb) {
+		ma, mb = mb, ma
+	}
+
+	n := 0
+	for id := range ma {
+		if _, ok := mb[id]; ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type sqlEdgeStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLEdgeStore returns an EdgeStore backed by a PostgreSQL table:
+//
+//	CREATE TABLE follow (
+//		from_id BIGINT NOT NULL,
+//		to_id BIGINT NOT NULL,
+//		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+//		PRIMARY KEY(from_id, to_id)
+//	)
+//
+// An index on to_id is recommended for reverse lookups.
+func NewSQLEdgeStore(db *sql.DB, table string) EdgeStore {
+	return &sqlEdgeStore{db: db, table: table}
+}
+
+func (s *sqlEdgeStore) AddEdge(ctx context.Context, from, to ID) error {
+	query := fmt.Sprintf("INSERT INTO %s (from_id, to_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", s.table)
+	_, err := s.db.ExecContext(ctx, query, from, to)
+	return err
+}
+
+func (s *sqlEdgeStore) RemoveEdge(ctx context.Context, from, to ID) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE from_id = $1 AND to_id = $2", s.table)
+	_, err := s.db.ExecContext(ctx, query, from, to)
+	return err
+}
+
+func (s *sqlEdgeStore) HasEdge(ctx context.Context, from, to ID) (bool, error) {
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE from_id = $1 AND to_id = $2)", s.table)
+	var ok bool
+	err := s.db.QueryRowContext(ctx, query, from, to).Scan(&ok)
+	return ok, err
+}
+
+func (s *sqlEdgeStore) Neighbors(ctx context.Context, id, after ID, limit int) ([]ID, error) {
+	query := fmt.Sprintf("SELECT to_id FROM %s WHERE from_id = $1 AND to_id > $2 ORDER BY to_id", s.table)
+	args := []interface{}{id, after}
+	if limit > 0 {
+		query += " LIMIT $3"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []ID
+	for rows.Next() {
+		var to ID
+		if err = rows.Scan(&to); err != nil {
+			return nil, err
+		}
+		ids = append(ids, to)
+	}
+	return ids, rows.Err()
+}
+
+func (s *sqlEdgeStore) MutualCount(ctx context.Context, a, b ID) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %[1]s x JOIN %[1]s y ON x.to_id = y.to_id
+WHERE x.from_id = $1 AND y.from_id = $2`, s.table)
+	var n int
+	err := s.db.QueryRowContext(ctx, query, a, b).Scan(&n)
+	return n, err
+}
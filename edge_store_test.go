@@ -0,0 +1,43 @@
+package gox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryEdgeStore(t *testing.T) {
+	ctx := context.Background()
+	s := gox.NewMemoryEdgeStore()
+	for _, to := range []gox.ID{3, 2, 4, 5} {
+		require.NoError(t, s.AddEdge(ctx, 1, to))
+	}
+	require.NoError(t, s.AddEdge(ctx, 1, 2))
+	for _, to := range []gox.ID{2, 3, 6} {
+		require.NoError(t, s.AddEdge(ctx, 7, to))
+	}
+
+	ids, err := s.Neighbors(ctx, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []gox.ID{2, 3, 4, 5}, ids)
+
+	ids, err = s.Neighbors(ctx, 1, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []gox.ID{3, 4}, ids)
+
+	n, err := s.MutualCount(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	require.NoError(t, s.RemoveEdge(ctx, 1, 2))
+	ok, err := s.HasEdge(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	n, err = s.MutualCount(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,153 @@
+package gox
+
+import (
+	"reflect"
+	"sync"
+)
+
+func init() {
+	MustRegisterAny(&Geofence{})
+	MustRegisterAny(&Location{})
+}
+
+// GeoCircle is a circle on earth, whose radius is in km
+type GeoCircle struct {
+	Center Coordinate `json:"center"`
+	Radius float64    `json:"radius"`
+}
+
+func (c *GeoCircle) Contains(p *Coordinate) bool {
+	return c.Center.DistanceTo(*p) <= c.Radius
+}
+
+// GeoPolygon is a simple polygon whose vertices are in order. It shouldn't cross the 180th meridian.
+type GeoPolygon struct {
+	Points []Coordinate `json:"points"`
+}
+
+// Contains reports whether p is inside polygon by ray casting
+func (g *GeoPolygon) Contains(p *Coordinate) bool {
+	in := false
+	n := len(g.Points)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := g.Points[i], g.Points[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+			p.Longitude < (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Geofence is an area defined by a circle or a polygon
+type Geofence struct {
+	Name    string      `json:"name,omitempty"`
+	Circle  *GeoCircle  `json:"circle,omitempty"`
+	Polygon *GeoPolygon `json:"polygon,omitempty"`
+}
+
+func (f *Geofence) Contains(l *Location) bool {
+	if l == nil {
+		return false
+	}
+
+	p := &Coordinate{Latitude: l.Latitude, Longitude: l.Longitude}
+	if f.Circle != nil && f.Circle.Contains(p) {
+		return true
+	}
+	return f.Polygon != nil && f.Polygon.Contains(p)
+}
+
+// GeoFilter filters items by geofences of viewer's region
+type GeoFilter struct {
+	mu     sync.RWMutex
+	fences map[RegionCode][]*Geofence
+}
+
+func NewGeoFilter() *GeoFilter {
+	return &GeoFilter{fences: make(map[RegionCode][]*Geofence)}
+}
+
+// Add adds fence to region. region can be a country code such as US, or a subdivision code such as US-CA.
+func (f *GeoFilter) Add(region RegionCode, fence *Geofence) {
+	f.mu.Lock()
+	f.fences[region] = append(f.fences[region], fence)
+	f.mu.Unlock()
+}
+
+// Fences returns geofences of region, falling back to geofences of its country
+func (f *GeoFilter) Fences(region RegionCode) []*Geofence {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if fences := f.fences[region]; len(fences) > 0 {
+		return fences
+	}
+	return f.fences[RegionCode(region.Country())]
+}
+
+// Filter returns items of list visible to viewers in region.
+// An item carrying a Location is visible if the location is inside any geofence of region.
+// Items without Location, and all items if region has no geofences, are visible.
+func (f *GeoFilter) Filter(list *AnyList, region RegionCode) *AnyList {
+	fences := f.Fences(region)
+	result := NewAnyList()
+	for i := 0; i < list.Size(); i++ {
+		a := list.Get(i)
+		if a == nil {
+			continue
+		}
+
+		l := ExtractLocation(a.Val())
+		if l == nil || len(fences) == 0 {
+			result.Append(a)
+			continue
+		}
+
+		for _, fence := range fences {
+			if fence.Contains(l) {
+				result.Append(a)
+				break
+			}
+		}
+	}
+	return result
+}
+
+var locationType = reflect.TypeOf(Location{})
+
+// ExtractLocation returns v if it's a Location, or the first Location field of struct v
+func ExtractLocation(v interface{}) *Location {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if rv.Type() == locationType {
+		l := rv.Interface().(Location)
+		return &l
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		if !f.CanInterface() {
+			continue
+		}
+
+		switch val := f.Interface().(type) {
+		case Location:
+			return &val
+		case *Location:
+			if val != nil {
+				return val
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type geoPost struct {
+	Text     string        `json:"text"`
+	Location *gox.Location `json:"location"`
+}
+
+func TestGeofence(t *testing.T) {
+	sf := &gox.Location{Name: "San Francisco", Latitude: 37.7749, Longitude: -122.4194}
+	la := &gox.Location{Name: "Los Angeles", Latitude: 34.0522, Longitude: -118.2437}
+	ny := &gox.Location{Name: "New York", Latitude: 40.7128, Longitude: -74.0060}
+
+	circle := &gox.Geofence{Circle: &gox.GeoCircle{Center: gox.Coordinate{Latitude: 37.77, Longitude: -122.42}, Radius: 50}}
+	assert.True(t, circle.Contains(sf))
+	assert.False(t, circle.Contains(la))
+
+	california := &gox.Geofence{Name: "California", Polygon: &gox.GeoPolygon{Points: []gox.Coordinate{
+		{Latitude: 42, Longitude: -124.4},
+		{Latitude: 42, Longitude: -120},
+		{Latitude: 39, Longitude: -120},
+		{Latitude: 35, Longitude: -114.6},
+		{Latitude: 32.5, Longitude: -114.6},
+		{Latitude: 32.5, Longitude: -117.1},
+		{Latitude: 34.5, Longitude: -120.6},
+		{Latitude: 40.4, Longitude: -124.4},
+	}}}
+	assert.True(t, california.Contains(sf))
+	assert.True(t, california.Contains(la))
+	assert.False(t, california.Contains(ny))
+
+	b, err := json.Marshal(gox.NewAny(california))
+	require.NoError(t, err)
+	var a gox.Any
+	require.NoError(t, json.Unmarshal(b, &a))
+	assert.Equal(t, california, a.Val())
+
+	f := gox.NewGeoFilter()
+	f.Add("US-CA", california)
+	f.Add("US", &gox.Geofence{Circle: &gox.GeoCircle{Center: gox.Coordinate{Latitude: 40.71, Longitude: -74}, Radius: 100}})
+	list := gox.NewAnyList(
+		gox.NewAny(&geoPost{Text: "sf", Location: sf}),
+		gox.NewAny(ny),
+		gox.NewAny("no location"),
+	)
+
+	result := f.Filter(list, "US-CA")
+	require.Equal(t, 2, result.Size())
+	assert.Equal(t, "no location", result.Get(1).Text())
+
+	result = f.Filter(list, "US-NY")
+	require.Equal(t, 2, result.Size())
+	assert.Equal(t, ny, result.Get(0).Val())
+
+	assert.Equal(t, 3, f.Filter(list, "JP-13").Size())
+}
@@ -9,4 +9,5 @@ require (
 	github.com/nyaruka/phonenumbers v1.0.42
 	github.com/pkg/errors v0.8.1
 	github.com/stretchr/testify v1.3.0
+	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
 )
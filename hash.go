@@ -0,0 +1,116 @@
+package gox
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"hash/fnv"
+	"math/bits"
+)
+
+// Fast non-cryptographic hashes for sharding and cache keys. Don't use them for signatures or passwords.
+
+// CRC32 returns IEEE CRC-32 checksum of b
+func CRC32(b []byte) uint32 {
+	return crc32.ChecksumIEEE(b)
+}
+
+// CRC32Str returns IEEE CRC-32 checksum of s
+func CRC32Str(s string) uint32 {
+	return crc32.ChecksumIEEE([]byte(s))
+}
+
+// FNV64a returns 64-bit FNV-1a hash of b
+func FNV64a(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// FNV64aStr returns 64-bit FNV-1a hash of s
+func FNV64aStr(s string) uint64 {
+	return FNV64a([]byte(s))
+}
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// Hash64 returns xxHash64 of b with seed 0, which is much faster than FNV for long input
+func Hash64(b []byte) uint64 {
+	n := len(b)
+	var h uint64
+	if n >= 32 {
+		p1, p2 := xxPrime1, xxPrime2
+		v1 := p1 + p2
+		v2 := p2
+		v3 := uint64(0)
+		v4 := -p1
+		for len(b) >= 32 {
+			v1 = xxRound(v1, binary.LittleEndian.Uint64(b[0:8]))
+			v2 = xxRound(v2, binary.LittleEndian.Uint64(b[8:16]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint64(b[16:24]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint64(b[24:32]))
+			b = b[32:]
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	} else {
+		h = xxPrime5
+	}
+
+	h += uint64(n)
+	for ; len(b) >= 8; b = b[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(b))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+
+	if len(b) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(b)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+
+	for _, c := range b {
+		h ^= uint64(c) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+// Hash64Str returns xxHash64 of s with seed 0
+func Hash64Str(s string) uint64 {
+	return Hash64([]byte(s))
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}
+
+// HashToShard maps s to a shard in [0, shards) with Hash64.
+// E.g. FixedNumberGetter(int64(HashToShard(hostname, 256))) can be used as shard id getter of SnakeIDGenerator.
+func HashToShard(s string, shards int) int {
+	if shards <= 0 {
+		panic("shards should be positive")
+	}
+	return int(Hash64Str(s) % uint64(shards))
+}
@@ -0,0 +1,32 @@
+package gox_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash64(t *testing.T) {
+	assert.Equal(t, uint64(0xef46db3751d8e999), gox.Hash64Str(""))
+	assert.Equal(t, uint64(0xd24ec4f1a98c6e5b), gox.Hash64Str("a"))
+	assert.Equal(t, uint64(0x44bc2cf5ad770999), gox.Hash64Str("abc"))
+	assert.Equal(t, uint64(0x0b242d361fda71bc), gox.Hash64Str(quickFox))
+	assert.Equal(t, uint64(0xcbf29ce484222325), gox.FNV64aStr(""))
+	assert.Equal(t, uint32(0x414fa339), gox.CRC32Str(quickFox))
+}
+
+func TestHashToShard(t *testing.T) {
+	counts := make([]int, 8)
+	for i := 0; i < 8000; i++ {
+		s := gox.HashToShard(strings.Repeat("k", i%50)+strconv.Itoa(i), 8)
+		counts[s]++
+	}
+	for _, c := range counts {
+		assert.InDelta(t, 1000, c, 200)
+	}
+	assert.Equal(t, gox.HashToShard("user:1", 16), gox.HashToShard("user:1", 16))
+	assert.Panics(t, func() { gox.HashToShard("a", 0) })
+}
@@ -0,0 +1,203 @@
+// Package hashchain links audit records with SHA-256 hashes, so that modifying, removing or reordering
+// any record breaks verification of all records after it.
+//
+// Each record's hash is Append(prev, canonical JSON of the record without its hash).
+// The first record's prev is the zero Hash unless a genesis hash is given.
+package hashchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gopub/gox"
+)
+
+type Hash [sha256.Size]byte
+
+// Append returns the hash of record chained after prev
+func Append(prev Hash, record []byte) Hash {
+	h := sha256.New()
+	h.Write(prev[:])
+	h.Write(record)
+	var v Hash
+	h.Sum(v[:0])
+	return v
+}
+
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(h) {
+		return h, errors.New("invalid hash")
+	}
+
+	for i := range h {
+		h[i] = b[i]
+	}
+	return h, nil
+}
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	v, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = v
+	return nil
+}
+
+// AuditRecord records a change of an object
+type AuditRecord struct {
+	Seq       int64     `json:"seq"`
+	ObjectID  gox.ID    `json:"object_id"`
+	ActorID   gox.ID    `json:"actor_id"`
+	Action    string    `json:"action"`
+	Payload   *gox.Any  `json:"payload,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	PrevHash  Hash      `json:"prev_hash"`
+	Hash      Hash      `json:"hash"`
+}
+
+// canonicalRecord fixes the encoding of AuditRecord, which must not depend on process settings such as gox.SetIDJSONStringMode
+type canonicalRecord struct {
+	Seq       int64       `json:"seq"`
+	ObjectID  json.Number `json:"object_id"`
+	ActorID   json.Number `json:"actor_id"`
+	Action    string      `json:"action"`
+	Payload   *gox.Any    `json:"payload,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	PrevHash  Hash        `json:"prev_hash"`
+}
+
+// Canonical returns JSON of r without Hash, with object keys sorted and without HTML escaping.
+// IDs are encoded as numbers regardless of gox.SetIDJSONStringMode.
+func (r *AuditRecord) Canonical() ([]byte, error) {
+	c := &canonicalRecord{
+		Seq:       r.Seq,
+		ObjectID:  json.Number(strconv.FormatInt(int64(r.ObjectID), 10)),
+		ActorID:   json.Number(strconv.FormatInt(int64(r.ActorID), 10)),
+		Action:    r.Action,
+		Payload:   r.Payload,
+		CreatedAt: r.CreatedAt.UTC(),
+		PrevHash:  r.PrevHash,
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// decoding into generic values sorts keys, including keys of payload
+	var v map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	e := json.NewEncoder(buf)
+	e.SetEscapeHTML(false)
+	if err = e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// ComputeHash returns the hash of r chained after r.PrevHash
+func (r *AuditRecord) ComputeHash() (Hash, error) {
+	b, err := r.Canonical()
+	if err != nil {
+		return Hash{}, err
+	}
+	return Append(r.PrevHash, b), nil
+}
+
+// Chain appends records in memory. Records should be persisted by the caller in order.
+type Chain struct {
+	head Hash
+	seq  int64
+}
+
+// NewChain creates a chain continuing from head, which is the hash of the last record or a genesis hash
+func NewChain(head Hash, lastSeq int64) *Chain {
+	return &Chain{head: head, seq: lastSeq}
+}
+
+// Append sets Seq, PrevHash and Hash of r, and moves head to r.
+// CreatedAt is set to now if it's zero, and truncated to microseconds as most databases store.
+func (c *Chain) Append(r *AuditRecord) error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	r.CreatedAt = r.CreatedAt.UTC().Truncate(time.Microsecond)
+	r.Seq = c.seq + 1
+	r.PrevHash = c.head
+	h, err := r.ComputeHash()
+	if err != nil {
+		return err
+	}
+	r.Hash = h
+	c.head = h
+	c.seq = r.Seq
+	return nil
+}
+
+func (c *Chain) Head() Hash {
+	return c.head
+}
+
+// VerifyError reports the first record failing verification
+type VerifyError struct {
+	Index  int
+	Seq    int64
+	Reason string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("record %d (seq %d): %s", e.Index, e.Seq, e.Reason)
+}
+
+// Verify checks records are chained from genesis in order and not modified.
+// It returns *VerifyError for a broken chain.
+func Verify(genesis Hash, records []*AuditRecord) error {
+	prev := genesis
+	var prevSeq int64
+	for i, r := range records {
+		if i > 0 && r.Seq != prevSeq+1 {
+			return &VerifyError{Index: i, Seq: r.Seq, Reason: "sequence gap"}
+		}
+
+		if r.PrevHash != prev {
+			return &VerifyError{Index: i, Seq: r.Seq, Reason: "previous hash mismatch"}
+		}
+
+		h, err := r.ComputeHash()
+		if err != nil {
+			return &VerifyError{Index: i, Seq: r.Seq, Reason: err.Error()}
+		}
+
+		if h != r.Hash {
+			return &VerifyError{Index: i, Seq: r.Seq, Reason: "hash mismatch"}
+		}
+		prev, prevSeq = r.Hash, r.Seq
+	}
+	return nil
+}
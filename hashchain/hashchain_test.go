@@ -0,0 +1,63 @@
+package hashchain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/gopub/gox/hashchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	c := hashchain.NewChain(hashchain.Hash{}, 0)
+	var records []*hashchain.AuditRecord
+	for i := 0; i < 3; i++ {
+		r := &hashchain.AuditRecord{
+			ObjectID: gox.ID(100 + i),
+			Action:   "update",
+			Payload:  gox.NewAny(&gox.Image{URL: "https://a.com/<1>.png", Width: i}),
+		}
+		require.NoError(t, c.Append(r))
+		records = append(records, r)
+	}
+	assert.Equal(t, records[2].Hash, c.Head())
+	require.NoError(t, hashchain.Verify(hashchain.Hash{}, records))
+
+	// records survive a JSON round trip, e.g. through storage
+	b, err := json.Marshal(records)
+	require.NoError(t, err)
+	var loaded []*hashchain.AuditRecord
+	require.NoError(t, json.Unmarshal(b, &loaded))
+	require.NoError(t, hashchain.Verify(hashchain.Hash{}, loaded))
+
+	loaded[1].Action = "delete"
+	err = hashchain.Verify(hashchain.Hash{}, loaded)
+	require.Error(t, err)
+	assert.Equal(t, 1, err.(*hashchain.VerifyError).Index)
+
+	err = hashchain.Verify(hashchain.Hash{}, []*hashchain.AuditRecord{records[0], records[2]})
+	require.Error(t, err)
+	assert.Equal(t, "sequence gap", err.(*hashchain.VerifyError).Reason)
+}
+
+func TestVerify_IDJSONStringMode(t *testing.T) {
+	c := hashchain.NewChain(hashchain.Hash{}, 0)
+	r := &hashchain.AuditRecord{ObjectID: gox.ID(1) << 60, ActorID: 7, Action: "create"}
+	require.NoError(t, c.Append(r))
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+
+	gox.SetIDJSONStringMode(true)
+	defer gox.SetIDJSONStringMode(false)
+	require.NoError(t, hashchain.Verify(hashchain.Hash{}, []*hashchain.AuditRecord{r}))
+
+	var loaded hashchain.AuditRecord
+	require.NoError(t, json.Unmarshal(b, &loaded))
+	require.NoError(t, hashchain.Verify(hashchain.Hash{}, []*hashchain.AuditRecord{&loaded}))
+
+	canonical, err := r.Canonical()
+	require.NoError(t, err)
+	assert.Contains(t, string(canonical), `"object_id":1152921504606846976`)
+}
@@ -2,11 +2,21 @@ package gox
 
 import (
 	"crypto/md5"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/gopub/log"
+	"math"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -36,18 +46,34 @@ var defaultIDGenerator IDGenerator
 
 func init() {
 	epoch = time.Date(2019, time.January, 2, 15, 4, 5, 0, time.UTC)
-	defaultIDGenerator = NewSnakeIDGenerator(DefaultShardBitSize, DefaultSeqBitSize, NextMilliseconds, GetShardIDByIP, defaultCounter)
+	defaultIDGenerator = NewSnakeIDGenerator(DefaultShardBitSize, DefaultSeqBitSize, nil, GetShardIDByEnv, nil)
 }
 
+// DefaultEpoch returns the epoch of generators which aren't created with WithEpoch or changed by SnakeIDGenerator.SetEpoch
+func DefaultEpoch() time.Time {
+	return epoch
+}
+
+const (
+	ErrOverflow    ErrorString = "parse error: overflow"
+	ErrInvalidChar ErrorString = "parse error: invalid character"
+)
+
+// ParseShortID parses s returned by ShortString or SortableString.
+// It returns ErrInvalidChar if s has characters out of the alphabet, and ErrOverflow if s is longer than 11 characters
+// or its value exceeds math.MaxInt64, so the result is never negative.
 func ParseShortID(s string) (ID, error) {
 	if len(s) == 0 {
-		return 0, errors.New("parse error")
+		return 0, errors.New("parse error: empty")
+	}
+
+	if len(s) > sortableShortSize {
+		return 0, ErrOverflow
 	}
 
-	var bytes = []byte(s)
 	var k int64
 	var v int64
-	for _, b := range bytes {
+	for _, b := range []byte(s) {
 		switch {
 		case b >= '0' && b <= '9':
 			v = int64(b - '0')
@@ -56,25 +82,38 @@ func ParseShortID(s string) (ID, error) {
 		case b >= 'a' && b <= 'z':
 			v = int64(36 + b - 'a')
 		default:
-			return 0, errors.New("parse error")
+			return 0, ErrInvalidChar
+		}
+
+		if k > (math.MaxInt64-v)/62 {
+			return 0, ErrOverflow
 		}
 		k = k*62 + v
 	}
 	return ID(k), nil
 }
 
+// ParsePrettyID parses s returned by PrettyString or SortablePrettyString case-insensitively.
+// Like ParseShortID, it returns ErrInvalidChar or ErrOverflow, and the result is never negative.
 func ParsePrettyID(s string) (ID, error) {
 	if len(s) == 0 {
-		return 0, errors.New("parse error")
+		return 0, errors.New("parse error: empty")
+	}
+
+	if len(s) > sortablePrettySize {
+		return 0, ErrOverflow
 	}
 
 	s = strings.ToUpper(s)
-	var bytes = []byte(s)
 	var k int64
-	for _, b := range bytes {
+	for _, b := range []byte(s) {
 		i := searchPrettyTable(b)
-		if i <= 0 {
-			return 0, errors.New("parse error")
+		if i < 0 {
+			return 0, ErrInvalidChar
+		}
+
+		if k > (math.MaxInt64-int64(i))/prettyTableSize {
+			return 0, ErrOverflow
 		}
 		k = k*prettyTableSize + int64(i)
 	}
@@ -129,6 +168,39 @@ func (i ID) ShortString() string {
 	}
 }
 
+// sortableShortSize is the length of ShortString of math.MaxInt64
+const sortableShortSize = 11
+
+// sortablePrettySize is the length of PrettyString of math.MaxInt64
+const sortablePrettySize = 13
+
+// SortableString returns ShortString left padded with '0' to 11 characters, so that the order of strings matches the order of ids.
+// It can be parsed by ParseShortID.
+func (i ID) SortableString() string {
+	return PadLeft(i.ShortString(), sortableShortSize, '0')
+}
+
+// SortablePrettyString returns PrettyString left padded with '1', which is the zero digit, to 13 characters,
+// so that the order of strings matches the order of ids. It can be parsed by ParsePrettyID.
+func (i ID) SortablePrettyString() string {
+	return PadLeft(i.PrettyString(), sortablePrettySize, rune(prettyTable[0]))
+}
+
+// Time returns the time when i was generated by default id generator, e.g. by NextID.
+// Use SnakeIDGenerator.Time for ids of other generators.
+func (i ID) Time() time.Time {
+	return defaultIDGenerator.(*SnakeIDGenerator).Time(i)
+}
+
+// Before reports whether i is less than other, i.e. generated before other by the same generator
+func (i ID) Before(other ID) bool {
+	return i < other
+}
+
+func (i ID) After(other ID) bool {
+	return i > other
+}
+
 func (i ID) Int() int64 {
 	return int64(i)
 }
@@ -168,6 +240,120 @@ func (i ID) Salt(v string) string {
 	return hex.EncodeToString(sum[:])
 }
 
+var idJSONStringMode int32
+
+// SetIDJSONStringMode makes ID marshal into JSON string such as "123456789",
+// as JavaScript loses precision of integers beyond 2^53
+func SetIDJSONStringMode(on bool) {
+	if on {
+		atomic.StoreInt32(&idJSONStringMode, 1)
+	} else {
+		atomic.StoreInt32(&idJSONStringMode, 0)
+	}
+}
+
+func (i ID) MarshalJSON() ([]byte, error) {
+	s := strconv.FormatInt(int64(i), 10)
+	if atomic.LoadInt32(&idJSONStringMode) == 1 {
+		return []byte(`"` + s + `"`), nil
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON accepts both number and string
+func (i *ID) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+
+	if n := len(s); n >= 2 && s[0] == '"' && s[n-1] == '"' {
+		s = s[1 : n-1]
+	}
+
+	return i.parseDecimal(s)
+}
+
+var _ encoding.TextMarshaler = ID(0)
+var _ encoding.TextUnmarshaler = (*ID)(nil)
+
+// MarshalText encodes id with ShortString, so that id can be used in url path, form and as JSON object key
+func (i ID) MarshalText() ([]byte, error) {
+	if i < 0 {
+		return nil, fmt.Errorf("invalid id %d", i)
+	}
+	return []byte(i.ShortString()), nil
+}
+
+func (i *ID) UnmarshalText(text []byte) error {
+	v, err := ParseShortID(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+var _ sql.Scanner = (*ID)(nil)
+var _ driver.Valuer = ID(0)
+
+// Scan accepts int64, []byte and string
+func (i *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*i = ID(v)
+		return nil
+	case []byte:
+		return i.parseDecimal(string(v))
+	case string:
+		return i.parseDecimal(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.ID", src)
+	}
+}
+
+func (i *ID) parseDecimal(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.ID: %v", s, err)
+	}
+	*i = ID(v)
+	return nil
+}
+
+func (i ID) Value() (driver.Value, error) {
+	return int64(i), nil
+}
+
+// NullID is an ID which can be null in database
+type NullID struct {
+	ID    ID
+	Valid bool
+}
+
+var _ sql.Scanner = (*NullID)(nil)
+var _ driver.Valuer = NullID{}
+
+func (n *NullID) Scan(src interface{}) error {
+	if src == nil {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+
+	if err := n.ID.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n NullID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.ID), nil
+}
+
 // ------------------------------
 // IDGenerator
 
@@ -179,87 +365,832 @@ type NumberGetter interface {
 	GetNumber() int64
 }
 
+// NumberGetterE is a NumberGetter which can fail, e.g. a shard id getter bound to a lease.
+// SnakeIDGenerator calls GetNumberE of such a shard id getter, and TryNextID returns its error.
+type NumberGetterE interface {
+	NumberGetter
+	GetNumberE() (int64, error)
+}
+
+// RollbackPolicy decides what SnakeIDGenerator does if clock moves backwards
+type RollbackPolicy int
+
+const (
+	// RollbackWait blocks until clock catches up with the last issued timestamp
+	RollbackWait RollbackPolicy = iota
+	// RollbackError fails id generation with ErrClockRollback
+	RollbackError
+	// RollbackDrift keeps issuing ids after the last issued timestamp as long as it's ahead of clock
+	// by no more than the drift allowance, otherwise it waits
+	RollbackDrift
+)
+
+const ErrClockRollback ErrorString = "clock moved backwards"
+
+// IDObserver receives events of SnakeIDGenerator, e.g. to export metrics.
+// Methods except IDsIssued are called while the generator is locked, so they should return quickly.
+type IDObserver interface {
+	// IDsIssued is called after n ids are generated
+	IDsIssued(n int)
+
+	// SequenceExhausted is called after all sequence numbers of a timestamp are used,
+	// and the generator waited for the next timestamp or drifted ahead of clock
+	SequenceExhausted(waited time.Duration)
+
+	// ClockRolledBack is called if clock is found behind the last issued timestamp by d
+	ClockRolledBack(d time.Duration)
+}
+
+// IDCounters is an IDObserver counting events, which can be read by metric collectors
+type IDCounters struct {
+	issued    int64
+	exhausted int64
+	rollbacks int64
+}
+
+var _ IDObserver = (*IDCounters)(nil)
+
+func (c *IDCounters) IDsIssued(n int) {
+	atomic.AddInt64(&c.issued, int64(n))
+}
+
+func (c *IDCounters) SequenceExhausted(waited time.Duration) {
+	atomic.AddInt64(&c.exhausted, 1)
+}
+
+func (c *IDCounters) ClockRolledBack(d time.Duration) {
+	atomic.AddInt64(&c.rollbacks, 1)
+}
+
+// Issued returns the number of generated ids
+func (c *IDCounters) Issued() int64 {
+	return atomic.LoadInt64(&c.issued)
+}
+
+// Exhausted returns the number of timestamps whose sequence numbers were used up
+func (c *IDCounters) Exhausted() int64 {
+	return atomic.LoadInt64(&c.exhausted)
+}
+
+// Rollbacks returns the number of clock rollbacks
+func (c *IDCounters) Rollbacks() int64 {
+	return atomic.LoadInt64(&c.rollbacks)
+}
+
+// SnakeIDGenerator composes id with timestamp, shard id and sequence number
+// If timestampGetter is nil, milliseconds elapsed since the generator's epoch on its clock is used.
+// If seqNumGetter is nil, a built-in sequence is used. It restarts from 0 in every timestamp unit,
+// and waits for the next timestamp once all 1<<seqBitSize numbers are used, so ids never collide.
 type SnakeIDGenerator struct {
 	seqBitSize   uint
 	shardBitSize uint
+	epoch        time.Time
 
 	timestampGetter NumberGetter
 	shardIDGetter   NumberGetter
 	seqNumGetter    NumberGetter
+
+	rollbackPolicy RollbackPolicy
+	maxDrift       time.Duration
+
+	unit     time.Duration
+	clock    Clock
+	observer IDObserver
+
+	persister IDStatePersister
+	saveAhead int64
+	reserved  int64
+
+	// recoveredUntil is the timestamp after the state recovered by SetStatePersister.
+	// Timestamps up to it may be issued ahead of clock, which isn't a rollback.
+	recoveredUntil int64
+
+	// shardLow places shard id below sequence number as Sonyflake does
+	shardLow bool
+
+	datacenterBitSize uint
+	datacenterID      int64
+
+	mu            sync.Mutex
+	lastTimestamp int64
+	seq           int64
 }
 
+// NewSnakeIDGenerator creates a generator. It panics if arguments are invalid, use NewSnakeIDGeneratorE to handle it.
 func NewSnakeIDGenerator(shardBitSize, seqBitSize uint, timestampGetter, shardIDGetter, seqNumGetter NumberGetter) *SnakeIDGenerator {
-	if seqBitSize < 1 || seqBitSize > 16 {
-		panic("seqBitSize should be [1,16]")
+	g, err := NewSnakeIDGeneratorE(shardBitSize, seqBitSize, timestampGetter, shardIDGetter, seqNumGetter)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// NewSnakeIDGeneratorE creates a generator, or returns an error if arguments are invalid
+func NewSnakeIDGeneratorE(shardBitSize, seqBitSize uint, timestampGetter, shardIDGetter, seqNumGetter NumberGetter) (*SnakeIDGenerator, error) {
+	return NewIDGenerator(
+		WithShardBits(shardBitSize),
+		WithSeqBits(seqBitSize),
+		WithTimestampGetter(timestampGetter),
+		WithShardIDGetter(shardIDGetter),
+		WithSeqNumGetter(seqNumGetter),
+	)
+}
+
+// IDOption configures a generator created by NewIDGenerator
+type IDOption func(g *SnakeIDGenerator)
+
+func WithEpoch(epoch time.Time) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.epoch = epoch
+	}
+}
+
+func WithShardBits(n uint) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.shardBitSize = n
+	}
+}
+
+func WithSeqBits(n uint) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.seqBitSize = n
+	}
+}
+
+// WithShardID sets a fixed shard id, which should be in [0, 1<<(shardBitSize-datacenterBitSize))
+func WithShardID(id int64) IDOption {
+	return WithShardIDGetter(fixedShardID(id))
+}
+
+// fixedShardID is set by WithShardID, which is checked by NewIDGenerator
+type fixedShardID int64
+
+func (n fixedShardID) GetNumber() int64 {
+	return int64(n)
+}
+
+func WithShardIDGetter(getter NumberGetter) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.shardIDGetter = getter
+	}
+}
+
+// WithTimestampGetter replaces the built-in timestamp, which makes WithEpoch, WithClock and WithTimestampUnit ineffective
+func WithTimestampGetter(getter NumberGetter) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.timestampGetter = getter
+	}
+}
+
+// WithSeqNumGetter replaces the built-in sequence
+func WithSeqNumGetter(getter NumberGetter) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.seqNumGetter = getter
+	}
+}
+
+func WithClock(c Clock) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.clock = c
 	}
+}
 
-	if seqNumGetter == nil {
-		panic("seqNumGetter is nil")
+func WithTimestampUnit(unit time.Duration) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.unit = unit
 	}
+}
 
-	if timestampGetter == nil {
-		panic("timestampGetter is nil")
+func WithRollbackPolicy(policy RollbackPolicy, maxDrift time.Duration) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.rollbackPolicy = policy
+		g.maxDrift = maxDrift
 	}
+}
 
-	if shardBitSize > 8 {
-		panic("shardBitSize should be [0,8]")
+// WithDatacenter splits shard id into high bitSize bits of datacenterID and the rest bits of worker id,
+// like the layout of Twitter Snowflake. Worker id is got by shard id getter.
+func WithDatacenter(bitSize uint, datacenterID int64) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.datacenterBitSize = bitSize
+		g.datacenterID = datacenterID
 	}
+}
 
-	if shardBitSize > 0 && shardIDGetter == nil {
-		panic("shardIDGetter is nil")
+func WithObserver(o IDObserver) IDOption {
+	return func(g *SnakeIDGenerator) {
+		g.observer = o
 	}
+}
 
-	if shardBitSize+seqBitSize >= 20 {
-		panic("shardBitSize + seqBitSize should be less than 20")
+// NewIDGenerator creates a generator configured by opts.
+// By default, it has DefaultShardBitSize, DefaultSeqBitSize, shard id from GetShardIDByEnv, and milliseconds since DefaultEpoch.
+func NewIDGenerator(opts ...IDOption) (*SnakeIDGenerator, error) {
+	g := &SnakeIDGenerator{
+		seqBitSize:    DefaultSeqBitSize,
+		shardBitSize:  DefaultShardBitSize,
+		epoch:         epoch,
+		shardIDGetter: GetShardIDByEnv,
+		unit:          time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
 
-	return &SnakeIDGenerator{
-		seqBitSize,
-		shardBitSize,
-		timestampGetter,
-		shardIDGetter,
-		seqNumGetter,
+	if err := g.validate(); err != nil {
+		return nil, err
 	}
+	return g, nil
 }
 
+// MinIDLifetime is the least duration which timestamps of a generator's layout can represent.
+// Shard and sequence bits are limited by it, e.g. the widest layouts in milliseconds are
+// 10-bit shard id and 12-bit sequence number as Twitter Snowflake, or 6-bit shard id and 16-bit sequence number.
+const MinIDLifetime = (1 << 41) * time.Millisecond
+
+func (g *SnakeIDGenerator) validate() error {
+	if g.seqBitSize < 1 || g.seqBitSize > 16 {
+		return errors.New("seqBitSize should be [1,16]")
+	}
+
+	if g.shardBitSize > 16 {
+		return errors.New("shardBitSize should be [0,16]")
+	}
+
+	if g.datacenterBitSize > g.shardBitSize {
+		return errors.New("datacenterBitSize should not exceed shardBitSize")
+	}
+
+	if g.datacenterID < 0 || g.datacenterID >= 1<<g.datacenterBitSize {
+		return errors.New("datacenterID is out of range")
+	}
+
+	if g.shardBitSize > g.datacenterBitSize && g.shardIDGetter == nil {
+		return errors.New("shardIDGetter is nil")
+	}
+
+	if n, ok := g.shardIDGetter.(fixedShardID); ok && (n < 0 || n >= 1<<(g.shardBitSize-g.datacenterBitSize)) {
+		return errors.New("shard id is out of range")
+	}
+
+	if g.unit <= 0 {
+		return errors.New("unit should be positive")
+	}
+
+	if int64(1)<<(63-g.shardBitSize-g.seqBitSize) < int64(MinIDLifetime/g.unit) {
+		return errors.New("shardBitSize + seqBitSize leaves timestamp shorter than MinIDLifetime")
+	}
+	return nil
+}
+
+// SonyflakeEpoch is the default start time of Sonyflake
+var SonyflakeEpoch = time.Date(2014, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+// NewSonyflakeCompatibleGenerator creates a generator with Sonyflake's layout:
+// 39-bit timestamp in 10ms units since SonyflakeEpoch, 8-bit sequence number and 16-bit machine id,
+// so that its ids sort correctly with ids generated by Sonyflake.
+func NewSonyflakeCompatibleGenerator(machineID int64) *SnakeIDGenerator {
+	if machineID < 0 || machineID > 0xffff {
+		panic("machineID should be [0,65535]")
+	}
+
+	g := &SnakeIDGenerator{
+		seqBitSize:    8,
+		shardBitSize:  16,
+		epoch:         SonyflakeEpoch,
+		shardIDGetter: fixedShardID(machineID),
+		unit:          10 * time.Millisecond,
+		shardLow:      true,
+	}
+	if err := g.validate(); err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Clone returns g itself. Generators sharing a shard id must share the sequence and timestamp state,
+// otherwise they issue duplicate ids. Create another generator with a different shard id for independent state.
 func (g *SnakeIDGenerator) Clone() *SnakeIDGenerator {
-	return &(*g)
+	return g
+}
+
+// SetEpoch sets the epoch of the built-in timestamp. It should be called before generating ids.
+func (g *SnakeIDGenerator) SetEpoch(epoch time.Time) {
+	g.mu.Lock()
+	g.epoch = epoch
+	g.mu.Unlock()
+}
+
+// Epoch returns the time which timestamps are counted from
+func (g *SnakeIDGenerator) Epoch() time.Time {
+	return g.epoch
+}
+
+// SetTimestampUnit sets the unit of the built-in timestamp, e.g. time.Millisecond (default), 10*time.Millisecond or time.Second.
+// A coarser unit makes timestamp last longer while fewer ids can be generated per unit.
+// It should be called before generating ids.
+func (g *SnakeIDGenerator) SetTimestampUnit(unit time.Duration) {
+	if unit <= 0 {
+		panic("unit should be positive")
+	}
+	g.mu.Lock()
+	g.unit = unit
+	g.mu.Unlock()
+}
+
+// TimestampUnit returns the unit of timestamp
+func (g *SnakeIDGenerator) TimestampUnit() time.Duration {
+	return g.unit
+}
+
+// SetClock sets the clock of the built-in timestamp, e.g. a MockClock to generate deterministic ids in tests.
+// Nil makes g use DefaultClock. Note g blocks if it waits for a MockClock which isn't advanced.
+func (g *SnakeIDGenerator) SetClock(c Clock) {
+	g.mu.Lock()
+	g.clock = c
+	g.mu.Unlock()
+}
+
+func (g *SnakeIDGenerator) now() time.Time {
+	if g.clock != nil {
+		return g.clock.Now()
+	}
+	return DefaultClock().Now()
+}
+
+// SetObserver sets o to receive events of g. Nil removes the observer.
+func (g *SnakeIDGenerator) SetObserver(o IDObserver) {
+	g.mu.Lock()
+	g.observer = o
+	g.mu.Unlock()
 }
 
+// SetRollbackPolicy sets the policy applied when clock moves backwards.
+// maxDrift is only used by RollbackDrift. It should be called before generating ids.
+func (g *SnakeIDGenerator) SetRollbackPolicy(policy RollbackPolicy, maxDrift time.Duration) {
+	g.mu.Lock()
+	g.rollbackPolicy = policy
+	g.maxDrift = maxDrift
+	g.mu.Unlock()
+}
+
+// NextID returns a new id. It panics if TryNextID fails, e.g. with ErrClockRollback under RollbackError policy.
 func (g *SnakeIDGenerator) NextID() ID {
-	id := g.timestampGetter.GetNumber() << (g.seqBitSize + g.shardBitSize)
-	if g.shardBitSize > 0 {
-		id |= g.shardIDGetter.GetNumber() << g.seqBitSize
+	id, err := g.TryNextID()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (g *SnakeIDGenerator) TryNextID() (ID, error) {
+	shardID, err := g.shardID()
+	if err != nil {
+		return 0, err
+	}
+	g.mu.Lock()
+	ts, seq, err := g.next()
+	o := g.observer
+	g.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	if o != nil {
+		o.IDsIssued(1)
+	}
+	return g.compose(ts, shardID, seq), nil
+}
+
+// NextIDs returns n ids. It panics if TryNextIDs fails, e.g. with ErrClockRollback under RollbackError policy.
+func (g *SnakeIDGenerator) NextIDs(n int) []ID {
+	ids, err := g.TryNextIDs(n)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// TryNextIDs returns n ids generated in a single critical section.
+// With the built-in sequence, all remaining sequence numbers of a timestamp are reserved at once.
+func (g *SnakeIDGenerator) TryNextIDs(n int) ([]ID, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	shardID, err := g.shardID()
+	if err != nil {
+		return nil, err
+	}
+	maxSeq := int64(1)<<g.seqBitSize - 1
+	ids := make([]ID, 0, n)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for len(ids) < n {
+		ts, seq, err := g.next()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, g.compose(ts, shardID, seq))
+		if g.seqNumGetter != nil {
+			continue
+		}
+
+		for g.seq < maxSeq && len(ids) < n {
+			g.seq++
+			ids = append(ids, g.compose(ts, shardID, g.seq))
+		}
+	}
+
+	if g.observer != nil {
+		g.observer.IDsIssued(len(ids))
+	}
+	return ids, nil
+}
+
+// next returns timestamp and sequence number for a new id. g.mu must be held
+func (g *SnakeIDGenerator) next() (int64, int64, error) {
+	now := g.timestamp()
+	ts := now
+	if ts < g.lastTimestamp && g.lastTimestamp <= g.recoveredUntil {
+		// continue after the recovered state
+		ts = g.lastTimestamp
+	} else if ts < g.lastTimestamp {
+		if g.observer != nil {
+			g.observer.ClockRolledBack(g.toDuration(g.lastTimestamp - now))
+		}
+		var err error
+		ts, err = g.handleRollback(now)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := g.reserve(ts); err != nil {
+		return 0, 0, err
+	}
+
+	if g.seqNumGetter != nil {
+		g.lastTimestamp = ts
+		return ts, g.seqNumGetter.GetNumber() % (1 << g.seqBitSize), nil
+	}
+
+	if ts == g.lastTimestamp {
+		g.seq = (g.seq + 1) % (1 << g.seqBitSize)
+		if g.seq == 0 && ts+1 <= g.recoveredUntil {
+			// the recovered timestamp is used up before restart
+			ts++
+		} else if g.seq == 0 {
+			// sequence space of this timestamp is exhausted
+			start := time.Now()
+			if g.rollbackPolicy == RollbackDrift && g.toDuration(ts+1-now) <= g.maxDrift {
+				ts++
+			} else {
+				ts = g.waitTimestamp(ts + 1)
+			}
+
+			if g.observer != nil {
+				g.observer.SequenceExhausted(time.Since(start))
+			}
+		}
+	} else {
+		g.seq = 0
+	}
+
+	if err := g.reserve(ts); err != nil {
+		return 0, 0, err
+	}
+	g.lastTimestamp = ts
+	return ts, g.seq, nil
+}
+
+func (g *SnakeIDGenerator) handleRollback(now int64) (int64, error) {
+	switch g.rollbackPolicy {
+	case RollbackError:
+		return 0, ErrClockRollback
+	case RollbackDrift:
+		if g.toDuration(g.lastTimestamp-now) <= g.maxDrift {
+			return g.lastTimestamp, nil
+		}
+	}
+	return g.waitTimestamp(g.lastTimestamp), nil
+}
+
+// waitTimestamp blocks until timestamp reaches min
+func (g *SnakeIDGenerator) waitTimestamp(min int64) int64 {
+	ts := g.timestamp()
+	for ts < min {
+		if d := g.toDuration(min - ts); d > time.Millisecond {
+			time.Sleep(d)
+		} else {
+			runtime.Gosched()
+		}
+		ts = g.timestamp()
+	}
+	return ts
+}
+
+func (g *SnakeIDGenerator) timestamp() int64 {
+	if g.timestampGetter != nil {
+		return g.timestampGetter.GetNumber()
+	}
+	return int64(g.now().Sub(g.epoch) / g.unit)
+}
+
+// toDuration converts a number of timestamp units to duration
+func (g *SnakeIDGenerator) toDuration(n int64) time.Duration {
+	return time.Duration(n) * g.unit
+}
+
+func (g *SnakeIDGenerator) shardID() (int64, error) {
+	workerBitSize := g.shardBitSize - g.datacenterBitSize
+	var worker int64
+	if workerBitSize > 0 {
+		if getter, ok := g.shardIDGetter.(NumberGetterE); ok {
+			n, err := getter.GetNumberE()
+			if err != nil {
+				return 0, err
+			}
+			worker = KeepRightBits(n, workerBitSize)
+		} else {
+			worker = KeepRightBits(g.shardIDGetter.GetNumber(), workerBitSize)
+		}
+	}
+	return g.datacenterID<<workerBitSize | worker, nil
+}
+
+func (g *SnakeIDGenerator) compose(ts, shardID, seq int64) ID {
+	id := ts << (g.seqBitSize + g.shardBitSize)
+	if g.shardLow {
+		id |= seq << g.shardBitSize
+		id |= shardID
+	} else {
+		id |= shardID << g.seqBitSize
+		id |= seq
 	}
-	id |= g.seqNumGetter.GetNumber() % (1 << g.seqBitSize)
 	return ID(id)
 }
 
+// IDComponents are the parts an id is composed of
+type IDComponents struct {
+	Timestamp int64
+	ShardID   int64
+	Seq       int64
+
+	// DatacenterID and WorkerID split ShardID if the generator has datacenter bits, otherwise WorkerID equals ShardID
+	DatacenterID int64
+	WorkerID     int64
+
+	// Time is converted from Timestamp. It's only accurate if timestamps are units since the generator's epoch.
+	Time time.Time
+}
+
+// Decompose splits id into timestamp, shard id and sequence number
+func (g *SnakeIDGenerator) Decompose(id ID) *IDComponents {
+	v := int64(id)
+	c := &IDComponents{
+		Seq:       KeepRightBits(v, g.seqBitSize),
+		ShardID:   KeepRightBits(v>>g.seqBitSize, g.shardBitSize),
+		Timestamp: v >> (g.seqBitSize + g.shardBitSize),
+	}
+	if g.shardLow {
+		c.ShardID = KeepRightBits(v, g.shardBitSize)
+		c.Seq = KeepRightBits(v>>g.shardBitSize, g.seqBitSize)
+	}
+	workerBitSize := g.shardBitSize - g.datacenterBitSize
+	c.DatacenterID = c.ShardID >> workerBitSize
+	c.WorkerID = KeepRightBits(c.ShardID, workerBitSize)
+	c.Time = g.epoch.Add(g.toDuration(c.Timestamp))
+	return c
+}
+
+// DatacenterID returns the datacenter id of id generated by g, which can be used to route requests
+func (g *SnakeIDGenerator) DatacenterID(id ID) int64 {
+	return g.Decompose(id).DatacenterID
+}
+
+// Time returns the time when id was generated by g.
+// Like Decompose, it assumes timestamps are units since the generator's epoch.
+func (g *SnakeIDGenerator) Time(id ID) time.Time {
+	ts := int64(id) >> (g.seqBitSize + g.shardBitSize)
+	return g.epoch.Add(g.toDuration(ts))
+}
+
+// MinIDAt returns the smallest id generated at t, which is useful for querying ids created in a time range, e.g.
+//
+//	WHERE id >= g.MinIDAt(begin) AND id <= g.MaxIDAt(end)
+//
+// Like Decompose, it assumes timestamps are units since the generator's epoch.
+func (g *SnakeIDGenerator) MinIDAt(t time.Time) ID {
+	return g.compose(g.timestampAt(t), 0, 0)
+}
+
+// MaxIDAt returns the largest id generated at t
+func (g *SnakeIDGenerator) MaxIDAt(t time.Time) ID {
+	return g.compose(g.timestampAt(t)+1, 0, 0) - 1
+}
+
+func (g *SnakeIDGenerator) timestampAt(t time.Time) int64 {
+	d := t.Sub(g.epoch)
+	if d < 0 {
+		return 0
+	}
+	return int64(d / g.toDuration(1))
+}
+
+// MinIDAt returns the smallest id generated at t by default id generator
+func MinIDAt(t time.Time) ID {
+	return defaultIDGenerator.(*SnakeIDGenerator).MinIDAt(t)
+}
+
+// MaxIDAt returns the largest id generated at t by default id generator
+func MaxIDAt(t time.Time) ID {
+	return defaultIDGenerator.(*SnakeIDGenerator).MaxIDAt(t)
+}
+
 type NumberGetterFunc func() int64
 
 func (f NumberGetterFunc) GetNumber() int64 {
 	return f()
 }
 
+// NextSecond returns seconds elapsed since the default epoch on DefaultClock
 var NextSecond NumberGetterFunc = func() int64 {
-	return time.Since(epoch).Nanoseconds() / 1e9
+	return int64(DefaultClock().Now().Sub(epoch) / time.Second)
 }
 
+// NextMilliseconds returns milliseconds elapsed since the default epoch on DefaultClock
 var NextMilliseconds NumberGetterFunc = func() int64 {
-	return time.Since(epoch).Nanoseconds() / 1e6
+	return int64(DefaultClock().Now().Sub(epoch) / time.Millisecond)
 }
 
+var fallbackShardID int64
+
+// SetFallbackShardID sets shard id returned by GetShardIDByIP if outbound ip can't be detected. The default is 0.
+func SetFallbackShardID(n int64) {
+	atomic.StoreInt64(&fallbackShardID, n)
+}
+
+var shardIDByIP struct {
+	once   sync.Once
+	id     int64
+	err    error
+	logged int32
+}
+
+// GetShardIDByIP returns shard id derived from outbound ip by GetShardIDByIPE,
+// or the fallback shard id if ip can't be detected, which is logged once
 var GetShardIDByIP NumberGetterFunc = func() int64 {
-	ip, err := GetOutboundIP()
+	n, err := GetShardIDByIPE()
 	if err != nil {
-		log.Fatal(err)
+		n = atomic.LoadInt64(&fallbackShardID)
+		if atomic.CompareAndSwapInt32(&shardIDByIP.logged, 0, 1) {
+			log.Error("Use fallback shard id", n, err)
+		}
 	}
+	return n
+}
 
-	ipBytes := []byte(ip)
-	var num int64 = 0
-	for i := 0; i < 8 && i < len(ipBytes); i++ {
-		num <<= 8
-		num |= int64(ipBytes[i])
+// GetShardIDByIPE returns shard id derived from outbound ip by ShardIDFromIP, which is resolved once,
+// so that shard id doesn't change with network and generating ids doesn't dial.
+// The error of detection is returned by every call.
+func GetShardIDByIPE() (int64, error) {
+	shardIDByIP.once.Do(func() {
+		ip, err := GetOutboundIP()
+		if err != nil {
+			shardIDByIP.err = err
+			return
+		}
+		shardIDByIP.id, shardIDByIP.err = ShardIDFromIP(ip)
+	})
+	return shardIDByIP.id, shardIDByIP.err
+}
+
+// ShardIDByInterface returns a NumberGetter deriving shard id from ip of interface name by ShardIDFromIP,
+// which pins shard derivation of multi-homed hosts to one NIC. It returns the fallback shard id on failure like GetShardIDByIP.
+func ShardIDByInterface(name string) NumberGetter {
+	return NumberGetterFunc(func() int64 {
+		ip, err := GetIPByInterface(name)
+		if err == nil {
+			var n int64
+			if n, err = ShardIDFromIP(ip); err == nil {
+				return n
+			}
+		}
+		n := atomic.LoadInt64(&fallbackShardID)
+		log.Error("Use fallback shard id", n, err)
+		return n
+	})
+}
+
+// ShardIDFromIP returns a non-negative hash of the canonical form of ip,
+// so that an IPv4 address and its IPv4-mapped IPv6 form result in the same shard id.
+func ShardIDFromIP(ip net.IP) (int64, error) {
+	ip = NormalizeIP(ip)
+	if ip == nil {
+		return 0, errors.New("invalid ip")
+	}
+	return int64(Hash64(ip) >> 1), nil
+}
+
+var shardIDByHostname struct {
+	once sync.Once
+	id   int64
+	err  error
+}
+
+// GetShardIDByHostname returns shard id derived from hostname by GetShardIDByHostnameE,
+// or the fallback shard id if hostname can't be read.
+// It suits environments where hostnames are unique per instance while ip detection is unstable.
+var GetShardIDByHostname NumberGetterFunc = func() int64 {
+	n, err := GetShardIDByHostnameE()
+	if err != nil {
+		n = atomic.LoadInt64(&fallbackShardID)
+		log.Error("Use fallback shard id", n, err)
+	}
+	return n
+}
+
+// GetShardIDByHostnameE returns non-negative FNV-1a hash of hostname, which is resolved once.
+// The generator keeps the right bits of it according to shard bit size.
+func GetShardIDByHostnameE() (int64, error) {
+	shardIDByHostname.once.Do(func() {
+		host, err := os.Hostname()
+		if err != nil {
+			shardIDByHostname.err = err
+			return
+		}
+		shardIDByHostname.id = ShardIDFromHostname(host)
+	})
+	return shardIDByHostname.id, shardIDByHostname.err
+}
+
+// ShardIDFromHostname returns non-negative FNV-1a hash of host, which is case-insensitive
+func ShardIDFromHostname(host string) int64 {
+	return int64(FNV64aStr(strings.ToLower(host)) >> 1)
+}
+
+// ShardIDEnvKey is the environment variable read by GetShardIDByEnv
+const ShardIDEnvKey = "GOX_SHARD_ID"
+
+// FixedNumberGetter returns a NumberGetter which always returns n, e.g. a shard id from config
+func FixedNumberGetter(n int64) NumberGetter {
+	return NumberGetterFunc(func() int64 {
+		return n
+	})
+}
+
+// ShardIDFromEnv parses shard id in environment variable key
+func ShardIDFromEnv(key string) (int64, bool) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || n < 0 {
+		log.Warnf("Invalid shard id %s=%s", key, v)
+		return 0, false
+	}
+	return n, true
+}
+
+// ShardIDFromPodOrdinal parses the ordinal of a Kubernetes StatefulSet pod in hostname, e.g. 3 in web-3
+func ShardIDFromPodOrdinal() (int64, bool) {
+	host, err := os.Hostname()
+	if err != nil {
+		return 0, false
 	}
-	return num
+
+	i := strings.LastIndexByte(host, '-')
+	if i < 0 {
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(host[i+1:], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+var shardIDByEnv struct {
+	once sync.Once
+	id   int64
+}
+
+// GetShardIDByEnv resolves shard id in order of precedence:
+// environment variable GOX_SHARD_ID, ordinal of StatefulSet pod in hostname, then GetShardIDByIP.
+// The result is resolved once. Use FixedNumberGetter to provide shard id explicitly.
+var GetShardIDByEnv NumberGetterFunc = func() int64 {
+	shardIDByEnv.once.Do(func() {
+		if n, ok := ShardIDFromEnv(ShardIDEnvKey); ok {
+			shardIDByEnv.id = n
+		} else if n, ok := ShardIDFromPodOrdinal(); ok {
+			shardIDByEnv.id = n
+		} else {
+			shardIDByEnv.id = GetShardIDByIP()
+		}
+	})
+	return shardIDByEnv.id
 }
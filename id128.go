@@ -0,0 +1,236 @@
+package gox
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+const shortAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ID128 is a 128-bit identifier in big endian
+type ID128 [16]byte
+
+func (i ID128) halves() (hi, lo uint64) {
+	return binary.BigEndian.Uint64(i[:8]), binary.BigEndian.Uint64(i[8:])
+}
+
+func newID128(hi, lo uint64) ID128 {
+	var i ID128
+	binary.BigEndian.PutUint64(i[:8], hi)
+	binary.BigEndian.PutUint64(i[8:], lo)
+	return i
+}
+
+func (i ID128) format(alphabet string) string {
+	var b [32]byte
+	hi, lo := i.halves()
+	base := uint64(len(alphabet))
+	n := len(b) - 1
+	for {
+		var r uint64
+		hi, r = hi/base, hi%base
+		lo, r = bits.Div64(r, lo, base)
+		b[n] = alphabet[r]
+		if hi == 0 && lo == 0 {
+			return string(b[n:])
+		}
+		n--
+	}
+}
+
+func parseID128(s string, base uint64, value func(c byte) int) (ID128, error) {
+	if len(s) == 0 {
+		return ID128{}, errors.New("parse error")
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := value(s[i])
+		if v < 0 {
+			return ID128{}, errors.New("parse error")
+		}
+
+		carry, l := bits.Mul64(lo, base)
+		overflow, h := bits.Mul64(hi, base)
+		h, c1 := bits.Add64(h, carry, 0)
+		l, c2 := bits.Add64(l, uint64(v), 0)
+		h, c3 := bits.Add64(h, 0, c2)
+		if overflow != 0 || c1 != 0 || c3 != 0 {
+			return ID128{}, errors.New("parse error: overflow")
+		}
+		hi, lo = h, l
+	}
+	return newID128(hi, lo), nil
+}
+
+// ShortString returns a representation of i in base62
+func (i ID128) ShortString() string {
+	return i.format(shortAlphabet)
+}
+
+// PrettyString returns a case-insensitive representation of i in the alphabet of ID.PrettyString
+func (i ID128) PrettyString() string {
+	return i.format(string(prettyTable[:]))
+}
+
+// String returns i in hex
+func (i ID128) String() string {
+	return hex.EncodeToString(i[:])
+}
+
+func (i ID128) IsZero() bool {
+	return i == ID128{}
+}
+
+// Time returns the time of id generated by ID128Generator
+func (i ID128) Time() time.Time {
+	ms := int64(i[0])<<40 | int64(i[1])<<32 | int64(binary.BigEndian.Uint32(i[2:6]))
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond))
+}
+
+func ParseShortID128(s string) (ID128, error) {
+	return parseID128(s, 62, func(c byte) int {
+		return strings.IndexByte(shortAlphabet, c)
+	})
+}
+
+func ParsePrettyID128(s string) (ID128, error) {
+	return parseID128(strings.ToUpper(s), prettyTableSize, func(c byte) int {
+		return searchPrettyTable(c)
+	})
+}
+
+// ParseID128 parses i in hex
+func ParseID128(s string) (ID128, error) {
+	var i ID128
+	if len(s) != 2*len(i) {
+		return i, errors.New("parse error: invalid length")
+	}
+
+	if _, err := hex.Decode(i[:], []byte(s)); err != nil {
+		return i, errors.New("parse error")
+	}
+	return i, nil
+}
+
+var _ encoding.TextMarshaler = ID128{}
+var _ encoding.TextUnmarshaler = (*ID128)(nil)
+
+// MarshalText encodes i with ShortString, which is also used in JSON
+func (i ID128) MarshalText() ([]byte, error) {
+	return []byte(i.ShortString()), nil
+}
+
+func (i *ID128) UnmarshalText(text []byte) error {
+	v, err := ParseShortID128(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+var _ sql.Scanner = (*ID128)(nil)
+var _ driver.Valuer = ID128{}
+
+// Scan accepts 16 bytes, hex and uuid strings
+func (i *ID128) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		if len(v) == len(i) {
+			for k := range i {
+				i[k] = v[k]
+			}
+			return nil
+		}
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("failed to parse %v into gox.ID128", src)
+	}
+
+	v, err := ParseID128(strings.Replace(s, "-", "", -1))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.ID128: %v", s, err)
+	}
+	*i = v
+	return nil
+}
+
+// Value returns 16 bytes, which can be stored in a bytea or binary(16) column
+func (i ID128) Value() (driver.Value, error) {
+	return i[:], nil
+}
+
+// ID128Generator composes ids with 48-bit milliseconds since unix epoch, a shard id and random bits.
+// Ids are ordered by millisecond, and not ordered within the same millisecond.
+type ID128Generator struct {
+	shardBitSize  uint
+	shardIDGetter NumberGetter
+}
+
+// NewID128Generator creates a generator. shardBitSize is in [0, 32], and the rest of 80 bits are random.
+func NewID128Generator(shardBitSize uint, shardIDGetter NumberGetter) *ID128Generator {
+	if shardBitSize > 32 {
+		panic("shardBitSize should be [0,32]")
+	}
+
+	if shardBitSize > 0 && shardIDGetter == nil {
+		panic("shardIDGetter is nil")
+	}
+
+	return &ID128Generator{
+		shardBitSize:  shardBitSize,
+		shardIDGetter: shardIDGetter,
+	}
+}
+
+func (g *ID128Generator) NextID() ID128 {
+	var i ID128
+	if _, err := rand.Read(i[6:]); err != nil {
+		panic(err)
+	}
+
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	hi, lo := i.halves()
+	hi = ms<<16 | hi&0xffff
+	if g.shardBitSize > 0 {
+		// shard id occupies the bits right after timestamp
+		shardID := uint64(KeepRightBits(g.shardIDGetter.GetNumber(), g.shardBitSize))
+		mask := uint64(1)<<g.shardBitSize - 1
+		shift := 80 - g.shardBitSize
+		if shift >= 64 {
+			hi = hi&^(mask<<(shift-64)) | shardID<<(shift-64)
+		} else {
+			hi = hi&^(mask>>(64-shift)) | shardID>>(64-shift)
+			lo = lo&^(mask<<shift) | shardID<<shift
+		}
+	}
+	return newID128(hi, lo)
+}
+
+// ShardID returns the shard id of i generated by g
+func (g *ID128Generator) ShardID(i ID128) int64 {
+	if g.shardBitSize == 0 {
+		return 0
+	}
+
+	hi, lo := i.halves()
+	shift := 80 - g.shardBitSize
+	mask := uint64(1)<<g.shardBitSize - 1
+	if shift >= 64 {
+		return int64(hi >> (shift - 64) & mask)
+	}
+	return int64((hi<<(64-shift) | lo>>shift) & mask)
+}
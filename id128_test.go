@@ -0,0 +1,77 @@
+package gox
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestID128_ShortString(t *testing.T) {
+	max := newID128(^uint64(0), ^uint64(0))
+	tests := []struct {
+		ID     ID128
+		Short  string
+		Pretty string
+	}{
+		{ID128{}, "0", "1"},
+		{newID128(0, 61), "z", "2T"},
+		{newID128(0, 62), "10", "2U"},
+		{max, "7n42DGM5Tflk9n8mt7Fhc7", ""},
+	}
+
+	for _, tc := range tests {
+		if s := tc.ID.ShortString(); s != tc.Short {
+			t.Fatal(tc.ID, s)
+		}
+
+		if tc.Pretty != "" && tc.ID.PrettyString() != tc.Pretty {
+			t.Fatal(tc.ID, tc.ID.PrettyString())
+		}
+
+		if id, err := ParseShortID128(tc.Short); err != nil || id != tc.ID {
+			t.Fatal(tc.Short, id, err)
+		}
+
+		if id, err := ParsePrettyID128(tc.ID.PrettyString()); err != nil || id != tc.ID {
+			t.Fatal(tc.ID.PrettyString(), id, err)
+		}
+	}
+
+	if _, err := ParseShortID128("7n42DGM5Tflk9n8mt7Fhc8"); err == nil {
+		t.Fatal("overflow")
+	}
+}
+
+func TestID128Generator(t *testing.T) {
+	for _, bits := range []uint{0, 8, 16, 20, 32} {
+		g := NewID128Generator(bits, FixedNumberGetter(0xabcdef12))
+		before := time.Now().Add(-time.Millisecond)
+		id := g.NextID()
+		if id.Time().Before(before) || id.Time().After(time.Now()) {
+			t.Fatal(id.Time())
+		}
+
+		if s := g.ShardID(id); s != KeepRightBits(0xabcdef12, bits) {
+			t.Fatal(bits, s)
+		}
+
+		if id == g.NextID() {
+			t.FailNow()
+		}
+	}
+
+	id := NewID128Generator(0, nil).NextID()
+	b, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v ID128
+	if err = json.Unmarshal(b, &v); err != nil || v != id {
+		t.Fatal(string(b), v, err)
+	}
+
+	if err = v.Scan(id.String()); err != nil || v != id {
+		t.Fatal(v, err)
+	}
+}
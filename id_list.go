@@ -1,5 +1,10 @@
 package gox
 
+import (
+	"sort"
+	"time"
+)
+
 type KeyIDList map[ID][]ID
 
 func (kl KeyIDList) Append(key ID, val ID) {
@@ -92,3 +97,23 @@ func IDSetToSlice(ids map[ID]bool) []ID {
 	}
 	return a
 }
+
+// SortIDs sorts ids in increasing order
+func SortIDs(ids []ID) {
+	sort.Sort(IDList(ids))
+}
+
+// IDRange is a range of ids, including both From and To
+type IDRange struct {
+	From ID `json:"from"`
+	To   ID `json:"to"`
+}
+
+func (r IDRange) Contains(i ID) bool {
+	return i >= r.From && i <= r.To
+}
+
+// IDRangeBetween returns the range of ids generated by default id generator from begin to end
+func IDRangeBetween(begin, end time.Time) IDRange {
+	return IDRange{From: MinIDAt(begin), To: MaxIDAt(end)}
+}
@@ -0,0 +1,108 @@
+package gox
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sync"
+)
+
+const ErrInvalidObfuscatedID ErrorString = "invalid obfuscated id"
+
+const idObfuscatorRounds = 6
+
+// IDObfuscator maps IDs to opaque IDs and back with a keyed Feistel network,
+// so that public identifiers don't reveal creation order or volume.
+// It's not a replacement of authorization: anyone who knows the key can recover IDs.
+type IDObfuscator struct {
+	pool sync.Pool
+}
+
+// NewIDObfuscator creates an IDObfuscator with key which should be at least 16 bytes
+func NewIDObfuscator(key []byte) *IDObfuscator {
+	if len(key) < 16 {
+		panic("key should be at least 16 bytes")
+	}
+
+	k := append([]byte(nil), key...)
+	o := &IDObfuscator{}
+	o.pool.New = func() interface{} {
+		return hmac.New(sha256.New, k)
+	}
+	return o
+}
+
+// Obfuscate maps non-negative id to another non-negative ID
+func (o *IDObfuscator) Obfuscate(id ID) ID {
+	if id < 0 {
+		panic("invalid id")
+	}
+
+	h := o.pool.Get().(hash.Hash)
+	defer o.pool.Put(h)
+	v := uint64(id)
+	// cycle walking keeps the result in the range of non-negative int64
+	for {
+		v = o.feistel(h, v, false)
+		if v>>63 == 0 {
+			return ID(v)
+		}
+	}
+}
+
+// Deobfuscate is the inverse of Obfuscate
+func (o *IDObfuscator) Deobfuscate(id ID) ID {
+	if id < 0 {
+		panic("invalid id")
+	}
+
+	h := o.pool.Get().(hash.Hash)
+	defer o.pool.Put(h)
+	v := uint64(id)
+	for {
+		v = o.feistel(h, v, true)
+		if v>>63 == 0 {
+			return ID(v)
+		}
+	}
+}
+
+// Encode returns ShortString of obfuscated id
+func (o *IDObfuscator) Encode(id ID) string {
+	return o.Obfuscate(id).ShortString()
+}
+
+// Decode parses s returned by Encode
+func (o *IDObfuscator) Decode(s string) (ID, error) {
+	id, err := ParseShortID(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if id < 0 {
+		return 0, ErrInvalidObfuscatedID
+	}
+	return o.Deobfuscate(id), nil
+}
+
+func (o *IDObfuscator) feistel(h hash.Hash, v uint64, reverse bool) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for i := 0; i < idObfuscatorRounds; i++ {
+		if reverse {
+			l, r = r^o.round(h, idObfuscatorRounds-1-i, l), l
+		} else {
+			l, r = r, l^o.round(h, i, r)
+		}
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+func (o *IDObfuscator) round(h hash.Hash, i int, v uint32) uint32 {
+	var b [5]byte
+	b[0] = byte(i)
+	binary.BigEndian.PutUint32(b[1:], v)
+	h.Reset()
+	h.Write(b[:])
+	return binary.BigEndian.Uint32(h.Sum(nil))
+}
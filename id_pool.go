@@ -0,0 +1,138 @@
+package gox
+
+import (
+	"time"
+
+	"github.com/gopub/log"
+)
+
+// IDPool pre-generates ids in background and serves them instantly, which suits bursts exceeding
+// the generator's sequence capacity per timestamp unit, e.g. import jobs.
+// Ids are reserved in advance, so their timestamps may be earlier than the time they are served.
+type IDPool struct {
+	gen          *SnakeIDGenerator
+	ids          chan ID
+	lowWatermark int
+	refill       chan struct{}
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+var _ IDGenerator = (*IDPool)(nil)
+
+// NewIDPool creates a pool holding up to size ids generated by g.
+// It refills after the number of ids in pool drops to lowWatermark.
+func NewIDPool(g *SnakeIDGenerator, size, lowWatermark int) *IDPool {
+	if g == nil {
+		panic("g is nil")
+	}
+
+	if size <= 0 {
+		panic("size should be positive")
+	}
+
+	if lowWatermark < 0 || lowWatermark >= size {
+		panic("lowWatermark should be [0, size)")
+	}
+
+	p := &IDPool{
+		gen:          g,
+		ids:          make(chan ID, size),
+		lowWatermark: lowWatermark,
+		refill:       make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go p.fill()
+	return p
+}
+
+// NextID returns a pre-generated id. It blocks while pool is being refilled if pool is empty,
+// and generates ids directly after pool is closed.
+func (p *IDPool) NextID() ID {
+	select {
+	case id := <-p.ids:
+		if len(p.ids) <= p.lowWatermark {
+			select {
+			case p.refill <- struct{}{}:
+			default:
+			}
+		}
+		return id
+	case <-p.done:
+		return p.gen.NextID()
+	}
+}
+
+// Len returns the number of ids in pool
+func (p *IDPool) Len() int {
+	return len(p.ids)
+}
+
+// Close stops refilling. Ids left in pool are discarded.
+func (p *IDPool) Close() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.done
+}
+
+const (
+	idPoolMinBackoff = time.Millisecond
+	idPoolMaxBackoff = time.Second
+)
+
+func (p *IDPool) fill() {
+	defer close(p.done)
+	// generate ids of one timestamp unit at a time, so that others using the generator aren't blocked for long
+	batch := 1 << p.gen.seqBitSize
+	var backoff time.Duration
+	for {
+		for n := cap(p.ids) - len(p.ids); n > 0; n = cap(p.ids) - len(p.ids) {
+			if n > batch {
+				n = batch
+			}
+
+			ids, err := p.gen.TryNextIDs(n)
+			if err != nil {
+				// back off exponentially, e.g. clock keeps behind under RollbackError policy
+				if backoff == 0 {
+					backoff = idPoolMinBackoff
+					log.Error(err)
+				} else if backoff < idPoolMaxBackoff {
+					backoff *= 2
+					if backoff >= idPoolMaxBackoff {
+						backoff = idPoolMaxBackoff
+						log.Errorf("Failed to generate ids, retrying every %v: %v", backoff, err)
+					}
+				}
+
+				t := time.NewTimer(backoff)
+				select {
+				case <-t.C:
+				case <-p.stop:
+					t.Stop()
+					return
+				}
+				continue
+			}
+			backoff = 0
+
+			for _, id := range ids {
+				select {
+				case p.ids <- id:
+				case <-p.stop:
+					return
+				}
+			}
+		}
+
+		select {
+		case <-p.refill:
+		case <-p.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gox
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIDPool(t *testing.T) {
+	g := NewSnakeIDGenerator(0, 4, nil, nil, nil)
+	p := NewIDPool(g, 1000, 200)
+	defer p.Close()
+	for i := 0; p.Len() < 1000; i++ {
+		if i > 200 {
+			t.Fatal("pool isn't filled", p.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 1000 ids need 63 milliseconds with 16 ids per millisecond
+	start := time.Now()
+	var last ID
+	for i := 0; i < 1000; i++ {
+		id := p.NextID()
+		if id <= last {
+			t.Fatal(id, last)
+		}
+		last = id
+	}
+
+	if d := time.Since(start); d > 30*time.Millisecond {
+		t.Fatal(d)
+	}
+
+	// refilled below low watermark
+	for i := 0; p.Len() == 0; i++ {
+		if i > 200 {
+			t.Fatal("pool isn't refilled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if id := p.NextID(); id <= last {
+		t.Fatal(id, last)
+	}
+
+	p.Close()
+	if id := p.NextID(); id <= last {
+		t.Fatal(id, last)
+	}
+}
+
+func TestIDPool_CloseWhileFailing(t *testing.T) {
+	var ts int64 = 100
+	counters := new(IDCounters)
+	g, err := NewIDGenerator(
+		WithTimestampGetter(NumberGetterFunc(func() int64 { return atomic.LoadInt64(&ts) })),
+		WithRollbackPolicy(RollbackError, 0),
+		WithObserver(counters),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.NextID()
+
+	// clock stays behind the last timestamp, so every attempt fails
+	atomic.StoreInt64(&ts, 50)
+	p := NewIDPool(g, 100, 10)
+	time.Sleep(100 * time.Millisecond)
+	if n := counters.Rollbacks(); n == 0 || n > 10 {
+		t.Fatal("expect backoff between retries", n)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close is blocked")
+	}
+
+	if p.Len() != 0 {
+		t.Fatal(p.Len())
+	}
+}
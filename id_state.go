@@ -0,0 +1,199 @@
+package gox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IDState means ids up to Timestamp and Seq may have been issued
+type IDState struct {
+	Timestamp int64 `json:"timestamp"`
+	Seq       int64 `json:"seq"`
+}
+
+// IDStatePersister saves IDState of a generator, so that it won't reissue ids after restart.
+// Load returns nil state if nothing is saved.
+type IDStatePersister interface {
+	Load() (*IDState, error)
+	Save(state *IDState) error
+}
+
+// SetStatePersister recovers state saved by p and makes g save state with p.
+// Instead of saving every id, g reserves timestamps ahead of clock by ahead, and saves the reserved state before using them.
+// After restart, g issues ids right after the recovered state even if it's ahead of clock, which isn't taken as a rollback.
+// Once the timestamp following the recovered state is used up, g waits for clock to catch up, or drifts under RollbackDrift
+// policy, so ahead is a trade-off between saving frequency and the delay after restart.
+func (g *SnakeIDGenerator) SetStatePersister(p IDStatePersister, ahead time.Duration) error {
+	if p == nil {
+		panic("p is nil")
+	}
+
+	if ahead < g.unit {
+		return errors.New("ahead should be at least one timestamp unit")
+	}
+
+	state, err := p.Load()
+	if err != nil {
+		return fmt.Errorf("load state: %v", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if state != nil && state.Timestamp >= g.lastTimestamp {
+		g.lastTimestamp = state.Timestamp
+		g.seq = state.Seq
+		g.reserved = state.Timestamp
+		g.recoveredUntil = state.Timestamp + 1
+	}
+	g.persister = p
+	g.saveAhead = int64(ahead / g.unit)
+	return nil
+}
+
+// reserve saves state before ts is used. g.mu must be held
+func (g *SnakeIDGenerator) reserve(ts int64) error {
+	if g.persister == nil || ts <= g.reserved {
+		return nil
+	}
+
+	state := &IDState{
+		Timestamp: ts + g.saveAhead,
+		Seq:       1<<g.seqBitSize - 1,
+	}
+	if err := g.persister.Save(state); err != nil {
+		return fmt.Errorf("save state: %v", err)
+	}
+	g.reserved = state.Timestamp
+	return nil
+}
+
+type fileIDStatePersister struct {
+	filename string
+}
+
+// NewFileIDStatePersister returns an IDStatePersister saving state in JSON file
+func NewFileIDStatePersister(filename string) IDStatePersister {
+	return &fileIDStatePersister{filename: filename}
+}
+
+func (p *fileIDStatePersister) Load() (*IDState, error) {
+	b, err := ioutil.ReadFile(p.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	state := new(IDState)
+	if err = json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save writes a temporary file and renames it, so that the file is never partially written
+func (p *fileIDStatePersister) Save(state *IDState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(p.filename), filepath.Base(p.filename)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), p.filename)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+const (
+	redisLoadStateScript = `return redis.call('GET', KEYS[1])`
+
+	// never moves state backwards, in case of an instance with stale state
+	redisSaveStateScript = `local v = redis.call('GET', KEYS[1])
+if v and tonumber(string.match(v, '^%d+')) >= tonumber(ARGV[2]) then return 0 end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1`
+)
+
+type redisIDStatePersister struct {
+	scripter RedisScripter
+	key      string
+	timeout  time.Duration
+}
+
+// NewRedisIDStatePersister returns an IDStatePersister saving state as "timestamp:seq" in key
+func NewRedisIDStatePersister(scripter RedisScripter, key string, timeout time.Duration) IDStatePersister {
+	return &redisIDStatePersister{
+		scripter: scripter,
+		key:      key,
+		timeout:  timeout,
+	}
+}
+
+func (p *redisIDStatePersister) Load() (*IDState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	v, err := p.scripter.Eval(ctx, redisLoadStateScript, []string{p.key})
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case []byte:
+		s = string(val)
+	default:
+		return nil, fmt.Errorf("unexpected reply %v", v)
+	}
+
+	fields := strings.Split(s, ":")
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid state %s", s)
+	}
+
+	state := new(IDState)
+	if state.Timestamp, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid state %s", s)
+	}
+
+	if state.Seq, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid state %s", s)
+	}
+	return state, nil
+}
+
+func (p *redisIDStatePersister) Save(state *IDState) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	v := strconv.FormatInt(state.Timestamp, 10) + ":" + strconv.FormatInt(state.Seq, 10)
+	_, err := p.scripter.Eval(ctx, redisSaveStateScript, []string{p.key}, v, state.Timestamp)
+	return err
+}
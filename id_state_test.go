@@ -0,0 +1,88 @@
+package gox
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnakeIDGenerator_SetStatePersister(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := NewFileIDStatePersister(filepath.Join(dir, "id_state.json"))
+
+	var now int64 = 1000
+	var timestamp NumberGetterFunc = func() int64 {
+		return now
+	}
+
+	g := NewSnakeIDGenerator(0, 8, timestamp, nil, nil)
+	if err = g.SetStatePersister(p, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	last := g.NextID()
+
+	state, err := p.Load()
+	if err != nil || state == nil || state.Timestamp != 1010 || state.Seq != 255 {
+		t.Fatal(state, err)
+	}
+
+	// restart within reserved timestamps
+	g = NewSnakeIDGenerator(0, 8, timestamp, nil, nil)
+	g.SetRollbackPolicy(RollbackDrift, 20*time.Millisecond)
+	if err = g.SetStatePersister(p, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	id := g.NextID()
+	if id <= last || id>>8 != 1011 {
+		t.Fatal(id>>8, last>>8)
+	}
+
+	if state, _ = p.Load(); state.Timestamp != 1021 {
+		t.Fatal(state)
+	}
+}
+
+func TestSnakeIDGenerator_SetStatePersister_RollbackError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p := NewFileIDStatePersister(filepath.Join(dir, "id_state.json"))
+	if err = p.Save(&IDState{Timestamp: 1010, Seq: 255}); err != nil {
+		t.Fatal(err)
+	}
+
+	var timestamp NumberGetterFunc = func() int64 {
+		return 1000
+	}
+	g := NewSnakeIDGenerator(0, 8, timestamp, nil, nil)
+	g.SetRollbackPolicy(RollbackError, 0)
+	counters := new(IDCounters)
+	g.SetObserver(counters)
+	if err = g.SetStatePersister(p, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := g.TryNextIDs(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, id := range ids {
+		if id>>8 != 1011 || int(id&0xff) != i {
+			t.Fatal(i, id>>8, id&0xff)
+		}
+	}
+
+	if counters.Rollbacks() != 0 || counters.Exhausted() != 0 {
+		t.Fatal(counters.Rollbacks(), counters.Exhausted())
+	}
+}
@@ -0,0 +1,270 @@
+package gox
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Index [256]int8
+
+func init() {
+	for i := range base58Index {
+		base58Index[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		base58Index[base58Alphabet[i]] = int8(i)
+	}
+}
+
+// Base58String returns a representation of id in base58 with Bitcoin alphabet, which excludes 0, O, I and l
+func (i ID) Base58String() string {
+	if i < 0 {
+		panic("invalid id")
+	}
+	var bytes [11]byte
+	k := int64(i)
+	n := len(bytes) - 1
+	for {
+		bytes[n] = base58Alphabet[k%58]
+		k /= 58
+		if k == 0 {
+			return string(bytes[n:])
+		}
+		n--
+	}
+}
+
+func ParseBase58ID(s string) (ID, error) {
+	if len(s) == 0 {
+		return 0, errors.New("parse error")
+	}
+
+	var k int64
+	for i := 0; i < len(s); i++ {
+		v := base58Index[s[i]]
+		if v < 0 {
+			return 0, errors.New("parse error")
+		}
+
+		if k > (math.MaxInt64-int64(v))/58 {
+			return 0, errors.New("parse error: overflow")
+		}
+		k = k*58 + int64(v)
+	}
+	return ID(k), nil
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordCheckAlphabet extends crockfordAlphabet with 5 check symbols for values 32-36
+const crockfordCheckAlphabet = crockfordAlphabet + "*~$=U"
+
+// CrockfordString returns a representation of id in Crockford's base32, which is case-insensitive
+// and excludes I, L, O and U to avoid transcription errors
+func (i ID) CrockfordString() string {
+	if i < 0 {
+		panic("invalid id")
+	}
+	var bytes [13]byte
+	k := int64(i)
+	n := len(bytes) - 1
+	for {
+		bytes[n] = crockfordAlphabet[k&31]
+		k >>= 5
+		if k == 0 {
+			return string(bytes[n:])
+		}
+		n--
+	}
+}
+
+// CrockfordCheckString returns CrockfordString followed by a mod 37 check symbol,
+// which detects single character errors and adjacent transpositions
+func (i ID) CrockfordCheckString() string {
+	return i.CrockfordString() + string(crockfordCheckAlphabet[int64(i)%37])
+}
+
+// ParseCrockfordID parses s in Crockford's base32. It's case-insensitive, maps I and L to 1 and O to 0, and ignores hyphens.
+func ParseCrockfordID(s string) (ID, error) {
+	var k int64
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '-' {
+			continue
+		}
+
+		v := crockfordValue(s[i])
+		if v < 0 || v >= 32 {
+			return 0, errors.New("parse error")
+		}
+
+		if k > (math.MaxInt64-int64(v))>>5 {
+			return 0, errors.New("parse error: overflow")
+		}
+		k = k<<5 | int64(v)
+		n++
+	}
+
+	if n == 0 {
+		return 0, errors.New("parse error")
+	}
+	return ID(k), nil
+}
+
+// ParseCrockfordCheckID parses s which ends with a check symbol, and rejects it if the check symbol doesn't match
+func ParseCrockfordCheckID(s string) (ID, error) {
+	if len(s) < 2 {
+		return 0, errors.New("parse error")
+	}
+
+	id, err := ParseCrockfordID(s[:len(s)-1])
+	if err != nil {
+		return 0, err
+	}
+
+	if crockfordValue(s[len(s)-1]) != int(int64(id)%37) {
+		return 0, errors.New("parse error: check symbol mismatch")
+	}
+	return id, nil
+}
+
+// ChecksumString returns CrockfordString followed by 2 check digits of ISO 7064 mod 97-10 over id,
+// which detect any single character error and adjacent transposition. It's suitable for coupon and invite codes.
+func (i ID) ChecksumString() string {
+	if i < 0 {
+		panic("invalid id")
+	}
+	return fmt.Sprintf("%s%02d", i.CrockfordString(), idChecksum(i))
+}
+
+// ParseChecksumID parses s returned by ChecksumString, and rejects it if check digits don't match.
+// Like ParseCrockfordID, it's case-insensitive and ignores hyphens.
+func ParseChecksumID(s string) (ID, error) {
+	s = strings.Replace(s, "-", "", -1)
+	n := len(s)
+	if n < 3 || s[n-2] < '0' || s[n-2] > '9' || s[n-1] < '0' || s[n-1] > '9' {
+		return 0, errors.New("parse error")
+	}
+
+	id, err := ParseCrockfordID(s[:n-2])
+	if err != nil {
+		return 0, err
+	}
+
+	if int(s[n-2]-'0')*10+int(s[n-1]-'0') != idChecksum(id) {
+		return 0, errors.New("parse error: checksum mismatch")
+	}
+	return id, nil
+}
+
+// idChecksum returns 98 - (id * 100 mod 97), so that id * 100 + checksum is a multiple of 97
+func idChecksum(i ID) int {
+	return 98 - int(int64(i)%97*100%97)
+}
+
+func crockfordValue(c byte) int {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+
+	switch c {
+	case 'O':
+		return 0
+	case 'I', 'L':
+		return 1
+	}
+
+	for i := 0; i < len(crockfordCheckAlphabet); i++ {
+		if crockfordCheckAlphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+var idPrefixes struct {
+	sync.RWMutex
+	entities map[string]string
+}
+
+// RegisterIDPrefix registers prefix for entity, e.g. "usr" for "user".
+// Once any prefix is registered, ParsePrefixed rejects unregistered prefixes.
+func RegisterIDPrefix(prefix, entity string) {
+	if !isValidIDPrefix(prefix) {
+		panic("invalid id prefix: " + prefix)
+	}
+
+	idPrefixes.Lock()
+	defer idPrefixes.Unlock()
+	if e, ok := idPrefixes.entities[prefix]; ok {
+		panic(fmt.Sprintf("id prefix %s is registered by %s", prefix, e))
+	}
+
+	if idPrefixes.entities == nil {
+		idPrefixes.entities = make(map[string]string)
+	}
+	idPrefixes.entities[prefix] = entity
+}
+
+// IDPrefixEntity returns the entity registered with prefix
+func IDPrefixEntity(prefix string) (string, bool) {
+	idPrefixes.RLock()
+	defer idPrefixes.RUnlock()
+	e, ok := idPrefixes.entities[prefix]
+	return e, ok
+}
+
+// FormatPrefixed returns prefix and ShortString of id joined by underscore, e.g. ord_8fK2mQ1
+func FormatPrefixed(prefix string, id ID) string {
+	if !isValidIDPrefix(prefix) {
+		panic("invalid id prefix: " + prefix)
+	}
+	return prefix + "_" + id.ShortString()
+}
+
+// ParsePrefixed parses s in the format of FormatPrefixed
+func ParsePrefixed(s string) (prefix string, id ID, err error) {
+	i := strings.LastIndexByte(s, '_')
+	if i < 0 {
+		return "", 0, errors.New("parse error: missing prefix")
+	}
+
+	prefix = s[:i]
+	if !isValidIDPrefix(prefix) {
+		return "", 0, errors.New("parse error: invalid prefix")
+	}
+
+	idPrefixes.RLock()
+	_, ok := idPrefixes.entities[prefix]
+	ok = ok || len(idPrefixes.entities) == 0
+	idPrefixes.RUnlock()
+	if !ok {
+		return "", 0, fmt.Errorf("parse error: unknown prefix %s", prefix)
+	}
+
+	id, err = ParseShortID(s[i+1:])
+	if err != nil {
+		return "", 0, err
+	}
+	return prefix, id, nil
+}
+
+// isValidIDPrefix reports whether prefix consists of lowercase letters, digits and underscores, starting with a letter
+func isValidIDPrefix(prefix string) bool {
+	if len(prefix) == 0 || prefix[0] < 'a' || prefix[0] > 'z' {
+		return false
+	}
+
+	for i := 1; i < len(prefix); i++ {
+		c := prefix[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
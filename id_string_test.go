@@ -0,0 +1,149 @@
+package gox
+
+import (
+	"math"
+	"testing"
+)
+
+func TestID_Base58String(t *testing.T) {
+	tests := []struct {
+		ID     ID
+		String string
+	}{
+		{0, "1"},
+		{57, "z"},
+		{58, "21"},
+		{math.MaxInt64, "NQm6nKp8qFC"},
+	}
+
+	for _, tc := range tests {
+		if s := tc.ID.Base58String(); s != tc.String {
+			t.Fatal(tc.ID, s)
+		}
+
+		if id, err := ParseBase58ID(tc.String); err != nil || id != tc.ID {
+			t.Fatal(tc.String, id, err)
+		}
+	}
+
+	if _, err := ParseBase58ID("0"); err == nil {
+		t.FailNow()
+	}
+
+	if _, err := ParseBase58ID("NQm6nKp8qFD"); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestID_CrockfordString(t *testing.T) {
+	var id ID = 1234
+	if s := id.CrockfordString(); s != "16J" {
+		t.Fatal(s)
+	}
+
+	// 1234 % 37 = 13
+	if s := id.CrockfordCheckString(); s != "16JD" {
+		t.Fatal(s)
+	}
+
+	for _, s := range []string{"16J", "16j", "i6J", "L-6-J"} {
+		if v, err := ParseCrockfordID(s); err != nil || v != id {
+			t.Fatal(s, v, err)
+		}
+	}
+
+	if v, err := ParseCrockfordCheckID("16jd"); err != nil || v != id {
+		t.Fatal(v, err)
+	}
+
+	for _, s := range []string{"16JE", "17JD", "61JD", "16J"} {
+		if _, err := ParseCrockfordCheckID(s); err == nil {
+			t.Fatal(s)
+		}
+	}
+
+	id = math.MaxInt64
+	if v, err := ParseCrockfordCheckID(id.CrockfordCheckString()); err != nil || v != id {
+		t.Fatal(v, err)
+	}
+}
+
+func TestID_ChecksumString(t *testing.T) {
+	var id ID = 1234
+	// 123400 % 97 = 16
+	if s := id.ChecksumString(); s != "16J82" {
+		t.Fatal(s)
+	}
+
+	for _, s := range []string{"16J82", "16j82", "16J-82", "i6J82"} {
+		if v, err := ParseChecksumID(s); err != nil || v != id {
+			t.Fatal(s, v, err)
+		}
+	}
+
+	for _, s := range []string{"16J83", "17J82", "61J82", "1J682", "16J", "82", "16JAB"} {
+		if _, err := ParseChecksumID(s); err == nil {
+			t.Fatal(s)
+		}
+	}
+
+	for _, id := range []ID{0, 1, 97, math.MaxInt64} {
+		if v, err := ParseChecksumID(id.ChecksumString()); err != nil || v != id {
+			t.Fatal(id, v, err)
+		}
+	}
+}
+
+func TestPrefixed(t *testing.T) {
+	s := FormatPrefixed("ord", 1234567)
+	if s != "ord_"+ID(1234567).ShortString() {
+		t.Fatal(s)
+	}
+
+	prefix, id, err := ParsePrefixed(s)
+	if err != nil || prefix != "ord" || id != 1234567 {
+		t.Fatal(prefix, id, err)
+	}
+
+	for _, s := range []string{"1234", "_1234", "Ord_1234", "ord_"} {
+		if _, _, err := ParsePrefixed(s); err == nil {
+			t.Fatal(s)
+		}
+	}
+
+	defer func() {
+		idPrefixes.entities = nil
+	}()
+	RegisterIDPrefix("usr", "user")
+	if e, _ := IDPrefixEntity("usr"); e != "user" {
+		t.Fatal(e)
+	}
+
+	if _, _, err := ParsePrefixed(s); err == nil {
+		t.Fatal("unregistered prefix")
+	}
+
+	if prefix, _, err := ParsePrefixed("usr_3kT9xQ"); err != nil || prefix != "usr" {
+		t.Fatal(prefix, err)
+	}
+}
+
+func TestIDObfuscator(t *testing.T) {
+	o := NewIDObfuscator([]byte("0123456789abcdef"))
+	for _, id := range []ID{0, 1, 2, 1000, 1 << 40, math.MaxInt64} {
+		s := o.Encode(id)
+		if s == id.ShortString() {
+			t.Fatal("not obfuscated", id)
+		}
+
+		v, err := o.Decode(s)
+		if err != nil || v != id {
+			t.Fatal(id, s, v, err)
+		}
+	}
+
+	other := NewIDObfuscator([]byte("fedcba9876543210"))
+	if o.Encode(1) == other.Encode(1) {
+		t.Fatal("same result with different keys")
+	}
+}
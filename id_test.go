@@ -1,8 +1,13 @@
 package gox
 
 import (
+	"encoding/json"
 	"math"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestID(t *testing.T) {
@@ -57,6 +62,81 @@ func TestID_PrettyString(t *testing.T) {
 	}
 }
 
+func TestID_SortableString(t *testing.T) {
+	var id ID = 123
+	if s := id.SortableString(); s != "0000000001z" {
+		t.Fatal(s)
+	}
+
+	if s := id.SortablePrettyString(); s != "111111111114M" {
+		t.Fatal(s)
+	}
+
+	ids := []ID{0, 1, 61, 62, 33, 34, 1 << 40, math.MaxInt64}
+	for _, id := range ids {
+		if v, err := ParseShortID(id.SortableString()); err != nil || v != id {
+			t.Fatal(id, v, err)
+		}
+
+		if v, err := ParsePrettyID(id.SortablePrettyString()); err != nil || v != id {
+			t.Fatal(id, v, err)
+		}
+	}
+
+	for i := 0; i < len(ids); i++ {
+		for j := 0; j < len(ids); j++ {
+			if (ids[i] < ids[j]) != (ids[i].SortableString() < ids[j].SortableString()) {
+				t.Fatal(ids[i], ids[j])
+			}
+
+			if (ids[i] < ids[j]) != (ids[i].SortablePrettyString() < ids[j].SortablePrettyString()) {
+				t.Fatal(ids[i], ids[j])
+			}
+		}
+	}
+
+	// '1' is the zero digit of pretty string
+	if v, err := ParsePrettyID("21"); err != nil || v != 34 {
+		t.Fatal(v, err)
+	}
+}
+
+func TestParseShortID_Strict(t *testing.T) {
+	var max ID = math.MaxInt64
+	if v, err := ParseShortID(max.ShortString()); err != nil || v != max {
+		t.Fatal(v, err)
+	}
+
+	if v, err := ParsePrettyID(max.PrettyString()); err != nil || v != max {
+		t.Fatal(v, err)
+	}
+
+	// math.MaxInt64 + 1
+	for _, s := range []string{"AzL8n0Y58m8", "zzzzzzzzzzz", "000000000001"} {
+		if _, err := ParseShortID(s); err != ErrOverflow {
+			t.Fatal(s, err)
+		}
+	}
+
+	for _, s := range []string{"ZZZZZZZZZZZZZ", "11111111111112"} {
+		if _, err := ParsePrettyID(s); err != ErrOverflow {
+			t.Fatal(s, err)
+		}
+	}
+
+	if _, err := ParseShortID("1-2"); err != ErrInvalidChar {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePrettyID("10"); err != ErrInvalidChar {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePrettyID(""); err == nil {
+		t.FailNow()
+	}
+}
+
 func TestNumberGetterFunc_GetNumber(t *testing.T) {
 	ip := GetShardIDByIP()
 	t.Logf("%0X", ip)
@@ -64,3 +144,469 @@ func TestNumberGetterFunc_GetNumber(t *testing.T) {
 	t.Logf("%0X %d", i1, i1)
 
 }
+
+func TestGetShardIDByIPE(t *testing.T) {
+	n1, err1 := GetShardIDByIPE()
+	n2, err2 := GetShardIDByIPE()
+	if n1 != n2 || err1 != err2 {
+		t.Fatal(n1, err1, n2, err2)
+	}
+
+	if err1 != nil && GetShardIDByIP() != atomic.LoadInt64(&fallbackShardID) {
+		t.FailNow()
+	}
+}
+
+func TestSnakeIDGenerator_NextID(t *testing.T) {
+	var shardID NumberGetterFunc = func() int64 {
+		return 3
+	}
+	g := NewSnakeIDGenerator(4, 4, NextMilliseconds, shardID, nil)
+	var mu sync.Mutex
+	ids := make(map[ID]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				id := g.NextID()
+				mu.Lock()
+				if ids[id] {
+					t.Errorf("duplicate id %d", id)
+				}
+				ids[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(ids) != 1600 {
+		t.FailNow()
+	}
+}
+
+func TestSnakeIDGenerator_Clone(t *testing.T) {
+	var shardID NumberGetterFunc = func() int64 {
+		return 3
+	}
+	g := NewSnakeIDGenerator(4, 4, NextMilliseconds, shardID, nil)
+	c := g.Clone()
+	ids := make(map[ID]bool)
+	for i := 0; i < 100; i++ {
+		for _, id := range []ID{g.NextID(), c.NextID()} {
+			if ids[id] {
+				t.Fatalf("duplicate id %d", id)
+			}
+			ids[id] = true
+		}
+	}
+}
+
+func TestSnakeIDGenerator_Rollback(t *testing.T) {
+	var now int64 = 1000
+	var timestamp NumberGetterFunc = func() int64 {
+		return now
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		g := NewSnakeIDGenerator(0, 2, timestamp, nil, nil)
+		g.SetRollbackPolicy(RollbackError, 0)
+		now = 1000
+		if _, err := g.TryNextID(); err != nil {
+			t.Fatal(err)
+		}
+		now = 999
+		if _, err := g.TryNextID(); err != ErrClockRollback {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		g := NewSnakeIDGenerator(0, 2, timestamp, nil, nil)
+		g.SetRollbackPolicy(RollbackDrift, 5*time.Millisecond)
+		now = 1000
+		last := g.NextID()
+		now = 998
+		for i := 0; i < 12; i++ {
+			id, err := g.TryNextID()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id <= last {
+				t.Fatalf("id %d is not greater than %d", id, last)
+			}
+			last = id
+		}
+		if int64(last>>2) != 1003 {
+			t.Fatal(last >> 2)
+		}
+	})
+}
+
+func TestNewSnakeIDGeneratorE(t *testing.T) {
+	if _, err := NewSnakeIDGeneratorE(0, 0, nil, nil, nil); err == nil {
+		t.Fatal("expected error of seqBitSize")
+	}
+
+	if _, err := NewSnakeIDGeneratorE(4, 8, nil, nil, nil); err == nil {
+		t.Fatal("expected error of nil shardIDGetter")
+	}
+
+	if g, err := NewSnakeIDGeneratorE(4, 8, nil, FixedNumberGetter(1), nil); err != nil || g == nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewIDGenerator(t *testing.T) {
+	clock := NewMockClock(time.Date(2020, time.January, 1, 0, 0, 1, 0, time.UTC))
+	g, err := NewIDGenerator(
+		WithEpoch(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		WithShardBits(4),
+		WithSeqBits(6),
+		WithShardID(3),
+		WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := g.NextID(); id != 1000<<10|3<<6 {
+		t.Fatal(id)
+	}
+
+	if _, err := NewIDGenerator(WithShardBits(4), WithShardIDGetter(nil)); err == nil {
+		t.Fatal("expected error of nil shardIDGetter")
+	}
+
+	if _, err := NewIDGenerator(WithTimestampUnit(0)); err == nil {
+		t.Fatal("expected error of unit")
+	}
+
+	for _, id := range []int64{-1, 16} {
+		if _, err := NewIDGenerator(WithShardID(id), WithShardBits(4)); err == nil {
+			t.Fatal("expected error of shard id", id)
+		}
+	}
+}
+
+func TestWithDatacenter(t *testing.T) {
+	g, err := NewIDGenerator(WithShardBits(8), WithDatacenter(3, 5), WithShardID(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := g.Decompose(g.NextID())
+	if c.ShardID != 5<<5|9 || c.DatacenterID != 5 || c.WorkerID != 9 {
+		t.Fatal(c)
+	}
+
+	if _, err = NewIDGenerator(WithShardBits(8), WithDatacenter(3, 5), WithShardID(32)); err == nil {
+		t.Fatal("expected error of shard id")
+	}
+
+	g, err = NewIDGenerator(WithShardBits(2), WithDatacenter(2, 3), WithShardIDGetter(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dc := g.DatacenterID(g.NextID()); dc != 3 {
+		t.Fatal(dc)
+	}
+
+	if _, err = NewIDGenerator(WithShardBits(8), WithDatacenter(3, 8)); err == nil {
+		t.Fatal("expected error of datacenterID")
+	}
+}
+
+func TestNewIDGenerator_SnowflakeLayout(t *testing.T) {
+	g, err := NewIDGenerator(WithShardBits(10), WithDatacenter(5, 31), WithShardID(31), WithSeqBits(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := g.Decompose(g.NextID())
+	if c.DatacenterID != 31 || c.WorkerID != 31 || c.Seq != 0 {
+		t.Fatal(c)
+	}
+
+	if _, err = NewIDGenerator(WithShardBits(6), WithShardID(1), WithSeqBits(16)); err != nil {
+		t.Fatal(err)
+	}
+
+	// timestamp would be shorter than MinIDLifetime
+	if _, err = NewIDGenerator(WithShardBits(11), WithShardID(1), WithSeqBits(12)); err == nil {
+		t.Fatal("expected error of layout")
+	}
+
+	if _, err = NewIDGenerator(WithShardBits(10), WithShardID(1), WithSeqBits(12), WithTimestampUnit(time.Microsecond)); err == nil {
+		t.Fatal("expected error of layout")
+	}
+}
+
+func TestSnakeIDGenerator_SetObserver(t *testing.T) {
+	var now int64 = 1000
+	var timestamp NumberGetterFunc = func() int64 {
+		return now
+	}
+	g := NewSnakeIDGenerator(0, 2, timestamp, nil, nil)
+	g.SetRollbackPolicy(RollbackDrift, 5*time.Millisecond)
+	c := new(IDCounters)
+	g.SetObserver(c)
+	g.NextIDs(4)
+	if c.Issued() != 4 || c.Exhausted() != 0 {
+		t.Fatal(c.Issued(), c.Exhausted())
+	}
+
+	g.NextID()
+	if c.Issued() != 5 || c.Exhausted() != 1 || c.Rollbacks() != 0 {
+		t.Fatal(c.Issued(), c.Exhausted(), c.Rollbacks())
+	}
+
+	now = 999
+	g.NextID()
+	if c.Issued() != 6 || c.Rollbacks() != 1 {
+		t.Fatal(c.Issued(), c.Rollbacks())
+	}
+}
+
+func TestSnakeIDGenerator_Decompose(t *testing.T) {
+	var shardID NumberGetterFunc = func() int64 {
+		return 5
+	}
+	g := NewSnakeIDGenerator(4, 6, nil, shardID, nil)
+	e := time.Date(2010, time.November, 4, 1, 42, 54, 0, time.UTC)
+	g.SetEpoch(e)
+	if !g.Epoch().Equal(e) {
+		t.FailNow()
+	}
+
+	before := time.Now()
+	id := g.NextID()
+	c := g.Decompose(id)
+	if c.ShardID != 5 || c.Seq != 0 {
+		t.Fatal(c)
+	}
+
+	if c.Time.Before(before.Add(-time.Millisecond)) || c.Time.After(time.Now()) {
+		t.Fatal(c.Time, before)
+	}
+}
+
+func TestSnakeIDGenerator_SetClock(t *testing.T) {
+	clock := NewMockClock(DefaultEpoch().Add(time.Second))
+	g := NewSnakeIDGenerator(0, 8, nil, nil, nil)
+	g.SetClock(clock)
+	if id := g.NextID(); id != 1000<<8 {
+		t.Fatal(id)
+	}
+
+	if id := g.NextID(); id != 1000<<8|1 {
+		t.Fatal(id)
+	}
+
+	clock.Advance(time.Millisecond)
+	if id := g.NextID(); id != 1001<<8 {
+		t.Fatal(id)
+	}
+
+	SetDefaultClock(clock)
+	defer SetDefaultClock(nil)
+	if n := NextMilliseconds(); n != 1001 {
+		t.Fatal(n)
+	}
+
+	if n := NextSecond(); n != 1 {
+		t.Fatal(n)
+	}
+}
+
+func TestID_Time(t *testing.T) {
+	before := time.Now()
+	tm := NextID().Time()
+	if tm.Before(before.Add(-time.Millisecond)) || tm.After(time.Now()) {
+		t.Fatal(tm, before)
+	}
+
+	g := NewSonyflakeCompatibleGenerator(1)
+	id := g.NextID()
+	if tm := g.Time(id); !tm.Equal(g.Decompose(id).Time) {
+		t.Fatal(tm, g.Decompose(id).Time)
+	}
+}
+
+func TestIDRange(t *testing.T) {
+	ids := []ID{3, 1, 2}
+	SortIDs(ids)
+	if ids[0] != 1 || ids[2] != 3 || !ids[0].Before(ids[1]) || !ids[2].After(ids[1]) {
+		t.Fatal(ids)
+	}
+
+	begin := time.Now()
+	id := NextID()
+	r := IDRangeBetween(begin.Add(-time.Millisecond), time.Now())
+	if !r.Contains(id) || r.Contains(r.From-1) || r.Contains(r.To+1) {
+		t.Fatal(r, id)
+	}
+}
+
+func TestSnakeIDGenerator_MinIDAt(t *testing.T) {
+	var shardID NumberGetterFunc = func() int64 {
+		return 3
+	}
+	g := NewSnakeIDGenerator(4, 6, nil, shardID, nil)
+	begin := time.Now()
+	id := g.NextID()
+	end := time.Now()
+	if id < g.MinIDAt(begin) || id > g.MaxIDAt(end) {
+		t.Fatal(id, g.MinIDAt(begin), g.MaxIDAt(end))
+	}
+
+	at := g.Epoch().Add(time.Hour)
+	min, max := g.MinIDAt(at), g.MaxIDAt(at)
+	if max-min != 1<<10-1 || g.Decompose(min).Timestamp != g.Decompose(max).Timestamp {
+		t.Fatal(min, max)
+	}
+
+	if g.MaxIDAt(at.Add(-time.Millisecond))+1 != min {
+		t.FailNow()
+	}
+}
+
+func TestNewSonyflakeCompatibleGenerator(t *testing.T) {
+	g := NewSonyflakeCompatibleGenerator(0x1234)
+	before := time.Now()
+	ids := g.NextIDs(300)
+	c := g.Decompose(ids[0])
+	if c.ShardID != 0x1234 || c.Seq != 0 || int64(ids[0])&0xffff != 0x1234 {
+		t.Fatal(c)
+	}
+
+	if ts, min := int64(ids[0])>>24, int64(before.Sub(SonyflakeEpoch)/(10*time.Millisecond)); ts < min || ts > min+1 {
+		t.Fatal(ts, min)
+	}
+
+	if c.Time.Before(before.Add(-10*time.Millisecond)) || c.Time.After(time.Now()) {
+		t.Fatal(c.Time, before)
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatal(ids[i-1], ids[i])
+		}
+	}
+
+	if c := g.Decompose(ids[1]); c.Seq != 1 || c.ShardID != 0x1234 {
+		t.Fatal(c)
+	}
+}
+
+func TestSnakeIDGenerator_SetTimestampUnit(t *testing.T) {
+	g := NewSnakeIDGenerator(0, 8, nil, nil, nil)
+	g.SetTimestampUnit(time.Second)
+	c := g.Decompose(g.NextID())
+	if c.Timestamp != int64(time.Since(g.Epoch())/time.Second) {
+		t.Fatal(c.Timestamp)
+	}
+}
+
+func TestSnakeIDGenerator_NextIDs(t *testing.T) {
+	g := NewSnakeIDGenerator(0, 4, nil, nil, nil)
+	ids := g.NextIDs(100)
+	if len(ids) != 100 {
+		t.FailNow()
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("%d is not greater than %d", ids[i], ids[i-1])
+		}
+	}
+
+	if id := g.NextID(); id <= ids[99] {
+		t.Fatalf("%d is not greater than %d", id, ids[99])
+	}
+}
+
+func TestID_JSON(t *testing.T) {
+	type Item struct {
+		ID ID `json:"id"`
+	}
+
+	b, err := json.Marshal(&Item{ID: 9007199254740993})
+	if err != nil || string(b) != `{"id":9007199254740993}` {
+		t.Fatal(string(b), err)
+	}
+
+	SetIDJSONStringMode(true)
+	defer SetIDJSONStringMode(false)
+	b, err = json.Marshal(&Item{ID: 9007199254740993})
+	if err != nil || string(b) != `{"id":"9007199254740993"}` {
+		t.Fatal(string(b), err)
+	}
+
+	for _, s := range []string{`{"id":123}`, `{"id":"123"}`} {
+		var item Item
+		if err = json.Unmarshal([]byte(s), &item); err != nil || item.ID != 123 {
+			t.Fatal(s, err)
+		}
+	}
+}
+
+func TestID_Scan(t *testing.T) {
+	for _, src := range []interface{}{int64(123), []byte("123"), "123"} {
+		var id ID
+		if err := id.Scan(src); err != nil || id != 123 {
+			t.Fatal(src, err)
+		}
+	}
+
+	var n NullID
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.FailNow()
+	}
+
+	if v, _ := n.Value(); v != nil {
+		t.FailNow()
+	}
+
+	if err := n.Scan(int64(5)); err != nil || !n.Valid || n.ID != 5 {
+		t.FailNow()
+	}
+}
+
+func TestID_MarshalText(t *testing.T) {
+	m := map[ID]string{123: "a"}
+	b, err := json.Marshal(m)
+	if err != nil || string(b) != `{"1z":"a"}` {
+		t.Fatal(string(b), err)
+	}
+
+	var m2 map[ID]string
+	if err = json.Unmarshal(b, &m2); err != nil || m2[123] != "a" {
+		t.Fatal(m2, err)
+	}
+}
+
+func TestShardIDFromEnv(t *testing.T) {
+	const key = "GOX_TEST_SHARD_ID"
+	os.Setenv(key, "12")
+	defer os.Unsetenv(key)
+	if n, ok := ShardIDFromEnv(key); !ok || n != 12 {
+		t.Fatal(n, ok)
+	}
+
+	os.Setenv(key, "x")
+	if _, ok := ShardIDFromEnv(key); ok {
+		t.FailNow()
+	}
+
+	os.Unsetenv(key)
+	if _, ok := ShardIDFromEnv(key); ok {
+		t.FailNow()
+	}
+
+	if FixedNumberGetter(3).GetNumber() != 3 {
+		t.FailNow()
+	}
+}
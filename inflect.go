@@ -0,0 +1,155 @@
+package gox
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var inflections = struct {
+	sync.RWMutex
+	plurals      map[string]string
+	singulars    map[string]string
+	uncountables map[string]bool
+}{
+	plurals:      make(map[string]string),
+	singulars:    make(map[string]string),
+	uncountables: make(map[string]bool),
+}
+
+func init() {
+	for s, p := range map[string]string{
+		"person": "people", "child": "children", "man": "men", "woman": "women", "mouse": "mice",
+		"goose": "geese", "tooth": "teeth", "foot": "feet", "ox": "oxen", "quiz": "quizzes",
+		"leaf": "leaves", "knife": "knives", "life": "lives", "wife": "wives", "half": "halves",
+		"wolf": "wolves", "shelf": "shelves", "calf": "calves", "thief": "thieves",
+		"hero": "heroes", "potato": "potatoes", "tomato": "tomatoes", "echo": "echoes",
+		"datum": "data", "medium": "media", "criterion": "criteria", "index": "indices", "matrix": "matrices", "vertex": "vertices",
+		"status": "statuses", "bus": "buses", "bonus": "bonuses", "campus": "campuses", "virus": "viruses",
+	} {
+		AddPluralRule(s, p)
+	}
+	AddUncountable("equipment", "information", "rice", "money", "species", "series", "fish", "sheep", "deer", "news", "metadata", "feedback")
+}
+
+// AddPluralRule overrides plural of singular for Pluralize and Singularize, e.g. AddPluralRule("cactus", "cacti")
+func AddPluralRule(singular, plural string) {
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	inflections.Lock()
+	inflections.plurals[singular] = plural
+	inflections.singulars[plural] = singular
+	inflections.Unlock()
+}
+
+// AddUncountable registers words whose singular and plural are the same
+func AddUncountable(words ...string) {
+	inflections.Lock()
+	for _, w := range words {
+		inflections.uncountables[strings.ToLower(w)] = true
+	}
+	inflections.Unlock()
+}
+
+// Pluralize returns plural of an English word, keeping its case, e.g. UserProfile to UserProfiles and Person to People.
+// Only the last word of identifiers in camel or snake case is changed.
+func Pluralize(word string) string {
+	return inflect(word, func(w string) string {
+		if p, ok := inflections.plurals[w]; ok {
+			return p
+		}
+
+		if _, ok := inflections.singulars[w]; ok {
+			return w
+		}
+
+		switch {
+		case hasAnySuffix(w, "s", "x", "z", "ch", "sh"):
+			if strings.HasSuffix(w, "is") {
+				// analysis, basis
+				return w[:len(w)-2] + "es"
+			}
+			return w + "es"
+		case strings.HasSuffix(w, "y") && len(w) > 1 && !isVowel(w[len(w)-2]):
+			return w[:len(w)-1] + "ies"
+		default:
+			return w + "s"
+		}
+	})
+}
+
+// Singularize returns singular of an English word, keeping its case, e.g. Categories to Category and people to person
+func Singularize(word string) string {
+	return inflect(word, func(w string) string {
+		if s, ok := inflections.singulars[w]; ok {
+			return s
+		}
+
+		if _, ok := inflections.plurals[w]; ok {
+			return w
+		}
+
+		switch {
+		case strings.HasSuffix(w, "ies") && len(w) > 3:
+			return w[:len(w)-3] + "y"
+		case strings.HasSuffix(w, "yses"):
+			return w[:len(w)-2] + "is"
+		case hasAnySuffix(w, "sses", "xes", "zes", "ches", "shes"):
+			return w[:len(w)-2]
+		case hasAnySuffix(w, "ss", "us", "is"):
+			return w
+		case strings.HasSuffix(w, "s"):
+			return w[:len(w)-1]
+		default:
+			return w
+		}
+	})
+}
+
+// inflect applies f to the last word of s in lower case, and restores the case
+func inflect(s string, f func(w string) string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+
+	last := words[len(words)-1]
+	i := strings.LastIndex(s, last)
+	lower := strings.ToLower(last)
+	inflections.RLock()
+	uncountable := inflections.uncountables[lower]
+	var res string
+	if !uncountable {
+		res = f(lower)
+	}
+	inflections.RUnlock()
+	if uncountable {
+		return s
+	}
+	return s[:i] + restoreCase(last, res) + s[i+len(last):]
+}
+
+// restoreCase applies case of orig to w rune by rune. Extra runes are upper case if orig is all upper case.
+func restoreCase(orig, w string) string {
+	o := []rune(orig)
+	upper := strings.ToUpper(orig) == orig && strings.ToLower(orig) != orig
+	r := []rune(w)
+	for i := range r {
+		if (i < len(o) && unicode.IsUpper(o[i])) || (i >= len(o) && upper) {
+			r[i] = unicode.ToUpper(r[i])
+		}
+	}
+	return string(r)
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
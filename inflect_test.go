@@ -0,0 +1,43 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		Singular string
+		Plural   string
+	}{
+		{"user", "users"},
+		{"category", "categories"},
+		{"day", "days"},
+		{"box", "boxes"},
+		{"address", "addresses"},
+		{"match", "matches"},
+		{"analysis", "analyses"},
+		{"person", "people"},
+		{"Person", "People"},
+		{"UserProfile", "UserProfiles"},
+		{"user_address", "user_addresses"},
+		{"ADDRESS", "ADDRESSES"},
+		{"sheep", "sheep"},
+		{"knife", "knives"},
+		{"OrderStatus", "OrderStatuses"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.Plural, gox.Pluralize(tc.Singular), tc.Singular)
+		assert.Equal(t, tc.Singular, gox.Singularize(tc.Plural), tc.Plural)
+	}
+
+	assert.Equal(t, "people", gox.Pluralize("people"))
+	assert.Equal(t, "status", gox.Singularize("status"))
+
+	gox.AddPluralRule("cactus", "cacti")
+	assert.Equal(t, "cacti", gox.Pluralize("cactus"))
+	assert.Equal(t, "Cactus", gox.Singularize("Cacti"))
+}
@@ -1,13 +1,51 @@
 package gox
 
 import (
-	"github.com/gopub/log"
+	"context"
 	"net"
+	"sync"
+	"time"
+
+	"github.com/gopub/log"
+)
+
+const (
+	// DefaultOutboundIPTarget is dialed by GetOutboundIP. No packet is sent as dialing udp only selects a route.
+	DefaultOutboundIPTarget = "8.8.8.8:80"
+
+	// DefaultOutboundIPv6Target is dialed by GetOutboundIP on IPv6-only hosts
+	DefaultOutboundIPv6Target = "[2001:4860:4860::8888]:80"
+
+	// DefaultOutboundIPTimeout applies to GetOutboundIPContext if ctx has no deadline
+	DefaultOutboundIPTimeout = 3 * time.Second
 )
 
-// Get preferred outbound ip of this machine
+// Get preferred outbound ip of this machine. It falls back to the IPv6 route if there's no IPv4 route.
 func GetOutboundIP() (net.IP, error) {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
+	ip, err := GetOutboundIPContext(context.Background(), DefaultOutboundIPTarget)
+	if err != nil {
+		if ip6, err6 := GetOutboundIPContext(context.Background(), DefaultOutboundIPv6Target); err6 == nil {
+			return ip6, nil
+		}
+	}
+	return ip, err
+}
+
+// GetOutboundIPContext returns local ip of the route to target, e.g. an internal address such as 10.0.0.1:53 in air-gapped networks.
+// target defaults to DefaultOutboundIPTarget. DefaultOutboundIPTimeout is applied if ctx has no deadline.
+func GetOutboundIPContext(ctx context.Context, target string) (net.IP, error) {
+	if target == "" {
+		target = DefaultOutboundIPTarget
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultOutboundIPTimeout)
+		defer cancel()
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", target)
 	if err != nil {
 		return nil, err
 	}
@@ -17,3 +55,42 @@ func GetOutboundIP() (net.IP, error) {
 	}
 	return localAddr.IP, nil
 }
+
+// OutboundIPRefreshInterval is how long GetCachedOutboundIP reuses the detected ip
+const OutboundIPRefreshInterval = time.Minute
+
+var cachedOutboundIP struct {
+	mu        sync.Mutex
+	ip        net.IP
+	err       error
+	updatedAt time.Time
+}
+
+// GetCachedOutboundIP returns the result of GetOutboundIP, which is refreshed every OutboundIPRefreshInterval.
+// The last detected ip is kept if a refresh fails, e.g. when network is down temporarily.
+func GetCachedOutboundIP() (net.IP, error) {
+	c := &cachedOutboundIP
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.updatedAt.IsZero() && time.Since(c.updatedAt) < OutboundIPRefreshInterval {
+		return c.ip, c.err
+	}
+
+	ip, err := GetOutboundIP()
+	c.updatedAt = time.Now()
+	if err != nil && c.ip != nil {
+		log.Warnf("Failed to refresh outbound ip: %v", err)
+		return c.ip, nil
+	}
+	c.ip, c.err = ip, err
+	return ip, err
+}
+
+// NormalizeIP returns the canonical form of ip: 4 bytes for IPv4 and IPv4-mapped IPv6 addresses, 16 bytes for IPv6.
+// It returns nil if ip is invalid.
+func NormalizeIP(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip.To16()
+}
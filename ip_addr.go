@@ -0,0 +1,211 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// IPAddr is an ip address which is comparable and can be used as map key.
+// IPv4 and IPv4-mapped IPv6 addresses are the same. The zero value is invalid.
+// It's encoded in canonical text such as 10.0.0.1 and 2001:db8::1 in JSON and SQL.
+type IPAddr struct {
+	b   [16]byte
+	is4 bool
+	ok  bool
+}
+
+var _ encoding.TextMarshaler = IPAddr{}
+var _ encoding.TextUnmarshaler = (*IPAddr)(nil)
+var _ json.Marshaler = IPAddr{}
+var _ json.Unmarshaler = (*IPAddr)(nil)
+var _ sql.Scanner = (*IPAddr)(nil)
+var _ driver.Valuer = IPAddr{}
+
+// IPAddrFrom converts ip into IPAddr, which is invalid if ip is invalid
+func IPAddrFrom(ip net.IP) IPAddr {
+	var a IPAddr
+	if v4 := ip.To4(); v4 != nil {
+		a.is4 = true
+		ip = v4.To16()
+	}
+
+	if len(ip) != net.IPv6len {
+		return IPAddr{}
+	}
+
+	for i := range a.b {
+		a.b[i] = ip[i]
+	}
+	a.ok = true
+	return a
+}
+
+func ParseIPAddr(s string) (IPAddr, error) {
+	a := IPAddrFrom(net.ParseIP(strings.TrimSpace(s)))
+	if !a.ok {
+		return a, fmt.Errorf("invalid ip: %s", s)
+	}
+	return a, nil
+}
+
+// IP returns 4-byte form of IPv4 and 16-byte form of IPv6, or nil if a is invalid
+func (a IPAddr) IP() net.IP {
+	if !a.ok {
+		return nil
+	}
+
+	if a.is4 {
+		return net.IP(append([]byte(nil), a.b[12:]...))
+	}
+	return net.IP(append([]byte(nil), a.b[:]...))
+}
+
+// Bytes returns 16-byte form of a, which can be stored in a binary(16) column. It returns nil if a is invalid.
+func (a IPAddr) Bytes() []byte {
+	if !a.ok {
+		return nil
+	}
+	return append([]byte(nil), a.b[:]...)
+}
+
+func (a IPAddr) IsValid() bool {
+	return a.ok
+}
+
+func (a IPAddr) Is4() bool {
+	return a.ok && a.is4
+}
+
+func (a IPAddr) IsLoopback() bool {
+	return a.ok && a.IP().IsLoopback()
+}
+
+// IsPrivate reports whether a is in private ranges of RFC 1918 and RFC 4193, or the shared range of RFC 6598
+func (a IPAddr) IsPrivate() bool {
+	return a.ok && isPrivateIP(a.IP())
+}
+
+// IsPublic reports whether a is a global unicast address out of private ranges
+func (a IPAddr) IsPublic() bool {
+	return a.ok && isPublicIP(a.IP())
+}
+
+// String returns canonical text of a, or an empty string if a is invalid
+func (a IPAddr) String() string {
+	if !a.ok {
+		return ""
+	}
+	return a.IP().String()
+}
+
+func (a IPAddr) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *IPAddr) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = IPAddr{}
+		return nil
+	}
+
+	v, err := ParseIPAddr(string(text))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalJSON encodes invalid a as null
+func (a IPAddr) MarshalJSON() ([]byte, error) {
+	if !a.ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(a.String())
+}
+
+func (a *IPAddr) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*a = IPAddr{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return a.UnmarshalText([]byte(s))
+}
+
+// Scan accepts text, and 4 or 16 bytes in binary which can't be parsed as text.
+// Use Binary to scan binary columns unambiguously.
+func (a *IPAddr) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = IPAddr{}
+		return nil
+	case []byte:
+		// drivers return text columns in []byte, e.g. "1::1" has 4 bytes
+		if ip := net.ParseIP(string(v)); ip != nil {
+			*a = IPAddrFrom(ip)
+			return nil
+		}
+
+		if len(v) == net.IPv4len || len(v) == net.IPv6len {
+			*a = IPAddrFrom(net.IP(v))
+			return nil
+		}
+		return a.UnmarshalText(v)
+	case string:
+		return a.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("failed to parse %v into gox.IPAddr", src)
+	}
+}
+
+// Value returns canonical text of a, or nil if a is invalid. Use Binary to store a in binary.
+func (a IPAddr) Value() (driver.Value, error) {
+	if !a.ok {
+		return nil, nil
+	}
+	return a.String(), nil
+}
+
+// Binary returns a Scanner and Valuer of a in 4 or 16 bytes for VARBINARY columns,
+// e.g. db.Exec(query, a.Binary()) and row.Scan(a.Binary())
+func (a *IPAddr) Binary() *BinaryIPAddr {
+	return (*BinaryIPAddr)(a)
+}
+
+// BinaryIPAddr is IPAddr stored in 4 or 16 bytes
+type BinaryIPAddr IPAddr
+
+var _ driver.Valuer = (*BinaryIPAddr)(nil)
+var _ sql.Scanner = (*BinaryIPAddr)(nil)
+
+func (a *BinaryIPAddr) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = BinaryIPAddr{}
+		return nil
+	case []byte:
+		if len(v) == net.IPv4len || len(v) == net.IPv6len {
+			*a = BinaryIPAddr(IPAddrFrom(net.IP(v)))
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to parse %v into gox.BinaryIPAddr", src)
+}
+
+// Value returns 4 bytes for IPv4, 16 bytes for IPv6, or nil if a is invalid
+func (a *BinaryIPAddr) Value() (driver.Value, error) {
+	if !a.ok {
+		return nil, nil
+	}
+	return []byte(IPAddr(*a).IP()), nil
+}
@@ -0,0 +1,103 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAddr(t *testing.T) {
+	a, err := gox.ParseIPAddr("10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, a.Is4())
+	assert.True(t, a.IsPrivate())
+	assert.False(t, a.IsPublic())
+	assert.Equal(t, net.IP{10, 0, 0, 1}, a.IP())
+	assert.Equal(t, a, gox.IPAddrFrom(net.ParseIP("::ffff:10.0.0.1")))
+
+	b, err := gox.ParseIPAddr("2001:DB8::1")
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1", b.String())
+	assert.False(t, b.Is4())
+	assert.True(t, b.IsPublic())
+	assert.True(t, gox.IPAddrFrom(net.IPv6loopback).IsLoopback())
+
+	_, err = gox.ParseIPAddr("10.0.0")
+	assert.Error(t, err)
+	assert.False(t, gox.IPAddr{}.IsValid())
+}
+
+func TestIPAddr_JSON(t *testing.T) {
+	var v struct {
+		IP    gox.IPAddr `json:"ip"`
+		Empty gox.IPAddr `json:"empty"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"ip":"192.168.1.1","empty":null}`), &v))
+	assert.Equal(t, "192.168.1.1", v.IP.String())
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ip":"192.168.1.1","empty":null}`, string(b))
+}
+
+func TestIPAddr_SQL(t *testing.T) {
+	a, _ := gox.ParseIPAddr("2001:db8::1")
+	v, err := a.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1", v)
+
+	var b gox.IPAddr
+	require.NoError(t, b.Scan(a.Bytes()))
+	assert.Equal(t, a, b)
+	require.NoError(t, b.Scan([]byte{10, 0, 0, 1}))
+	assert.Equal(t, "10.0.0.1", b.String())
+	require.NoError(t, b.Scan("10.0.0.2"))
+	assert.Equal(t, "10.0.0.2", b.String())
+	require.NoError(t, b.Scan(nil))
+	assert.False(t, b.IsValid())
+	v, err = b.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestIPAddr_SQLText(t *testing.T) {
+	for _, s := range []string{"2001:db8:85a3::1", "1::1", "10.0.0.1"} {
+		a, err := gox.ParseIPAddr(s)
+		require.NoError(t, err)
+		v, err := a.Value()
+		require.NoError(t, err)
+
+		// drivers return text columns in []byte
+		var b gox.IPAddr
+		require.NoError(t, b.Scan([]byte(v.(string))))
+		assert.Equal(t, a, b, s)
+	}
+}
+
+func TestIPAddr_Binary(t *testing.T) {
+	for _, s := range []string{"2001:db8:85a3::1", "1::1", "10.0.0.1"} {
+		a, err := gox.ParseIPAddr(s)
+		require.NoError(t, err)
+		v, err := a.Binary().Value()
+		require.NoError(t, err)
+		if a.Is4() {
+			assert.Len(t, v, 4)
+		} else {
+			assert.Len(t, v, 16)
+		}
+
+		var b gox.IPAddr
+		require.NoError(t, b.Binary().Scan(v))
+		assert.Equal(t, a, b, s)
+	}
+
+	var b gox.IPAddr
+	assert.Error(t, b.Binary().Scan([]byte("10.0.0.1")))
+	require.NoError(t, b.Binary().Scan(nil))
+	v, err := b.Binary().Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
@@ -1,6 +1,10 @@
 package gox
 
-import "testing"
+import (
+	"context"
+	"net"
+	"testing"
+)
 
 func TestGetOutboundIP(t *testing.T) {
 	ip, err := GetOutboundIP()
@@ -11,3 +15,64 @@ func TestGetOutboundIP(t *testing.T) {
 
 	t.Log(ip.String())
 }
+
+func TestGetOutboundIPContext(t *testing.T) {
+	ip, err := GetOutboundIPContext(context.Background(), "127.0.0.1:80")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ip.IsLoopback() {
+		t.Fatal(ip)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = GetOutboundIPContext(ctx, "localhost:80"); err == nil {
+		t.Fatal("expect error")
+	}
+}
+
+func TestShardIDFromIP(t *testing.T) {
+	v4, err := ShardIDFromIP(net.ParseIP("10.0.0.1").To4())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapped, err := ShardIDFromIP(net.ParseIP("::ffff:10.0.0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v4 != mapped || v4 < 0 {
+		t.Fatal(v4, mapped)
+	}
+
+	v6, err := ShardIDFromIP(net.ParseIP("2001:db8::1"))
+	if err != nil || v6 < 0 || v6 == v4 {
+		t.Fatal(v6, err)
+	}
+
+	if _, err = ShardIDFromIP(net.IP{1, 2}); err == nil {
+		t.Fatal("expect error")
+	}
+}
+
+func TestGetShardIDByHostname(t *testing.T) {
+	n, err := GetShardIDByHostnameE()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n < 0 || n != GetShardIDByHostname() {
+		t.Fatal(n)
+	}
+
+	if ShardIDFromHostname("web-1.example.com") != ShardIDFromHostname("WEB-1.example.com") {
+		t.Fatal("hostname should be case-insensitive")
+	}
+
+	if ShardIDFromHostname("web-1") == ShardIDFromHostname("web-2") {
+		t.Fatal("expect different shard ids")
+	}
+}
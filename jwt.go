@@ -0,0 +1,155 @@
+package gox
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	ErrInvalidJWT     ErrorString = "invalid jwt"
+	ErrJWTSignature   ErrorString = "jwt signature mismatch"
+	ErrJWTExpired     ErrorString = "jwt expired"
+	ErrJWTNotValidYet ErrorString = "jwt not valid yet"
+)
+
+const (
+	jwtHS256 = "HS256"
+	jwtEdDSA = "EdDSA"
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// SignJWT returns a HS256 JSON Web Token of claims signed with key.
+// Claim iat is set to now, and exp is set to now+ttl if ttl is positive. Times are read from DefaultClock.
+func SignJWT(claims map[string]interface{}, key []byte, ttl time.Duration) (string, error) {
+	return signJWT(jwtHS256, claims, ttl, func(data []byte) []byte {
+		return HMACSHA256Bytes(key, data)
+	})
+}
+
+// VerifyJWT verifies a HS256 token signed with key and returns its claims.
+// Numbers in claims are decoded as float64 as in encoding/json.
+func VerifyJWT(token string, key []byte) (map[string]interface{}, error) {
+	return verifyJWT(jwtHS256, token, func(data, sig []byte) bool {
+		return SecureCompareBytes(sig, HMACSHA256Bytes(key, data))
+	})
+}
+
+// SignJWTEdDSA returns an EdDSA JSON Web Token of claims signed with Ed25519 key. It's the same as SignJWT otherwise.
+func SignJWTEdDSA(claims map[string]interface{}, key ed25519.PrivateKey, ttl time.Duration) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", ErrUnsupportedKey
+	}
+	return signJWT(jwtEdDSA, claims, ttl, func(data []byte) []byte {
+		return ed25519.Sign(key, data)
+	})
+}
+
+// VerifyJWTEdDSA verifies an EdDSA token signed with the private key of pub and returns its claims
+func VerifyJWTEdDSA(token string, pub ed25519.PublicKey) (map[string]interface{}, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrUnsupportedKey
+	}
+	return verifyJWT(jwtEdDSA, token, func(data, sig []byte) bool {
+		return ed25519.Verify(pub, data, sig)
+	})
+}
+
+func signJWT(alg string, claims map[string]interface{}, ttl time.Duration, sign func(data []byte) []byte) (string, error) {
+	now := DefaultClock().Now()
+	c := make(map[string]interface{}, len(claims)+2)
+	for k, v := range claims {
+		c[k] = v
+	}
+	c["iat"] = now.Unix()
+	if ttl > 0 {
+		c["exp"] = now.Add(ttl).Unix()
+	}
+
+	header, err := json.Marshal(&jwtHeader{Alg: alg, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	data := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+	return data + "." + enc.EncodeToString(sign([]byte(data))), nil
+}
+
+func verifyJWT(alg, token string, verify func(data, sig []byte) bool) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidJWT
+	}
+
+	enc := base64.RawURLEncoding
+	b, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidJWT
+	}
+
+	var h jwtHeader
+	if err = json.Unmarshal(b, &h); err != nil {
+		return nil, ErrInvalidJWT
+	}
+
+	// alg must be checked before verifying signature, otherwise a token may be forged with alg none or another algorithm
+	if h.Alg != alg {
+		return nil, ErrInvalidJWT
+	}
+
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidJWT
+	}
+
+	if !verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return nil, ErrJWTSignature
+	}
+
+	b, err = enc.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidJWT
+	}
+
+	var claims map[string]interface{}
+	if err = json.Unmarshal(b, &claims); err != nil || claims == nil {
+		return nil, ErrInvalidJWT
+	}
+
+	now := DefaultClock().Now().Unix()
+	if exp, ok := claims["exp"]; ok {
+		v, ok := exp.(float64)
+		if !ok {
+			return nil, ErrInvalidJWT
+		}
+
+		if now >= int64(v) {
+			return nil, ErrJWTExpired
+		}
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		v, ok := nbf.(float64)
+		if !ok {
+			return nil, ErrInvalidJWT
+		}
+
+		if now < int64(v) {
+			return nil, ErrJWTNotValidYet
+		}
+	}
+	return claims, nil
+}
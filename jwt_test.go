@@ -0,0 +1,62 @@
+package gox_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWT(t *testing.T) {
+	key := []byte("secret")
+	token, err := gox.SignJWT(map[string]interface{}{"sub": "1234"}, key, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(strings.Split(token, ".")))
+	claims, err := gox.VerifyJWT(token, key)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", claims["sub"])
+	assert.NotNil(t, claims["exp"])
+
+	_, err = gox.VerifyJWT(token, []byte("secret2"))
+	assert.Equal(t, gox.ErrJWTSignature, err)
+	_, err = gox.VerifyJWT(token[:len(token)-2], key)
+	assert.Error(t, err)
+	_, err = gox.VerifyJWT("abc", key)
+	assert.Equal(t, gox.ErrInvalidJWT, err)
+
+	// alg none must be rejected
+	parts := strings.Split(token, ".")
+	_, err = gox.VerifyJWT("eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0."+parts[1]+".", key)
+	assert.Equal(t, gox.ErrInvalidJWT, err)
+
+	token, err = gox.SignJWT(map[string]interface{}{"exp": 1}, key, 0)
+	require.NoError(t, err)
+	_, err = gox.VerifyJWT(token, key)
+	assert.Equal(t, gox.ErrJWTExpired, err)
+
+	token, err = gox.SignJWT(map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()}, key, 0)
+	require.NoError(t, err)
+	_, err = gox.VerifyJWT(token, key)
+	assert.Equal(t, gox.ErrJWTNotValidYet, err)
+}
+
+func TestJWTEdDSA(t *testing.T) {
+	pub, priv, err := gox.GenerateKeypair()
+	require.NoError(t, err)
+	token, err := gox.SignJWTEdDSA(map[string]interface{}{"sub": "1234"}, priv, time.Minute)
+	require.NoError(t, err)
+	claims, err := gox.VerifyJWTEdDSA(token, pub)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", claims["sub"])
+
+	pub2, _, err := gox.GenerateKeypair()
+	require.NoError(t, err)
+	_, err = gox.VerifyJWTEdDSA(token, pub2)
+	assert.Equal(t, gox.ErrJWTSignature, err)
+
+	_, err = gox.VerifyJWT(token, pub)
+	assert.Equal(t, gox.ErrInvalidJWT, err)
+}
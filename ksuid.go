@@ -0,0 +1,170 @@
+package gox
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// KSUIDEpoch is the unix time in seconds which KSUID timestamps start from
+const KSUIDEpoch = 1400000000
+
+const ksuidStringSize = 27
+
+// KSUID is a 160-bit identifier composed of a 32-bit timestamp in seconds since KSUIDEpoch and 128-bit random payload.
+// Its string form is 27 characters in base62, which is sortable by time.
+// As the payload is random, it needs no shard coordination.
+type KSUID [20]byte
+
+var defaultKSUIDGenerator = NewKSUIDGenerator(nil)
+
+// NewKSUID returns a KSUID of current time
+func NewKSUID() KSUID {
+	k, err := defaultKSUIDGenerator.NewAt(time.Now())
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func ParseKSUID(s string) (KSUID, error) {
+	var k KSUID
+	if len(s) != ksuidStringSize {
+		return k, errors.New("parse error: invalid length")
+	}
+
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(shortAlphabet, s[i])
+		if v < 0 {
+			return k, errors.New("parse error")
+		}
+
+		// k = k*62 + v
+		carry := v
+		for j := len(k) - 1; j >= 0; j-- {
+			carry += int(k[j]) * 62
+			k[j] = byte(carry)
+			carry >>= 8
+		}
+
+		if carry != 0 {
+			return KSUID{}, errors.New("parse error: overflow")
+		}
+	}
+	return k, nil
+}
+
+func KSUIDFromBytes(b []byte) (KSUID, error) {
+	var k KSUID
+	if len(b) != len(k) {
+		return k, errors.New("invalid length")
+	}
+	for i := range k {
+		k[i] = b[i]
+	}
+	return k, nil
+}
+
+func (k KSUID) String() string {
+	var b [ksuidStringSize]byte
+	n := k
+	for i := len(b) - 1; i >= 0; i-- {
+		// n, r = n/62, n%62
+		r := 0
+		for j := range n {
+			r = r<<8 | int(n[j])
+			n[j] = byte(r / 62)
+			r %= 62
+		}
+		b[i] = shortAlphabet[r]
+	}
+	return string(b[:])
+}
+
+func (k KSUID) Bytes() []byte {
+	return k[:]
+}
+
+// Timestamp returns seconds since KSUIDEpoch
+func (k KSUID) Timestamp() uint32 {
+	return binary.BigEndian.Uint32(k[:4])
+}
+
+func (k KSUID) Time() time.Time {
+	return time.Unix(int64(k.Timestamp())+KSUIDEpoch, 0)
+}
+
+// Payload returns the random part
+func (k KSUID) Payload() []byte {
+	return k[4:]
+}
+
+func (k KSUID) IsZero() bool {
+	return k == KSUID{}
+}
+
+func (k KSUID) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *KSUID) UnmarshalText(text []byte) error {
+	v, err := ParseKSUID(string(text))
+	if err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+func (k *KSUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return k.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(k) {
+			for i := range k {
+				k[i] = v[i]
+			}
+			return nil
+		}
+		return k.UnmarshalText(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.KSUID", src)
+	}
+}
+
+func (k KSUID) Value() (driver.Value, error) {
+	return k.String(), nil
+}
+
+// KSUIDGenerator generates KSUIDs with random payload
+type KSUIDGenerator struct {
+	entropy io.Reader
+}
+
+// NewKSUIDGenerator creates a generator reading random bytes from entropy. If entropy is nil, crypto/rand is used.
+func NewKSUIDGenerator(entropy io.Reader) *KSUIDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return &KSUIDGenerator{entropy: entropy}
+}
+
+func (g *KSUIDGenerator) NewAt(t time.Time) (KSUID, error) {
+	ts := t.Unix() - KSUIDEpoch
+	if ts < 0 || ts > 1<<32-1 {
+		return KSUID{}, errors.New("time is out of range")
+	}
+
+	var k KSUID
+	binary.BigEndian.PutUint32(k[:4], uint32(ts))
+	if _, err := io.ReadFull(g.entropy, k[4:]); err != nil {
+		return KSUID{}, err
+	}
+	return k, nil
+}
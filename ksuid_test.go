@@ -0,0 +1,43 @@
+package gox_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKSUID(t *testing.T) {
+	payload, _ := hex.DecodeString("B5A1CD34B5F99D1154FB6853345C9735")
+	g := gox.NewKSUIDGenerator(bytes.NewReader(payload))
+	at := time.Unix(107608047+gox.KSUIDEpoch, 0)
+	k, err := g.NewAt(at)
+	require.NoError(t, err)
+	assert.Equal(t, "0ujtsYcgvSTl8PAuAdqWYSMnLOv", k.String())
+	assert.True(t, k.Time().Equal(at))
+	assert.Equal(t, payload, k.Payload())
+
+	v, err := gox.ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	require.NoError(t, err)
+	assert.Equal(t, k, v)
+
+	assert.Equal(t, "000000000000000000000000000", gox.KSUID{}.String())
+	max, err := gox.ParseKSUID("aWgEPTl1tmebfsQzFP4bxwgy80V")
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, 20), max.Bytes())
+
+	_, err = gox.ParseKSUID("aWgEPTl1tmebfsQzFP4bxwgy80W")
+	assert.Error(t, err)
+	_, err = gox.ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLO-")
+	assert.Error(t, err)
+
+	k1, k2 := gox.NewKSUID(), gox.NewKSUID()
+	assert.NotEqual(t, k1, k2)
+	v, err = gox.KSUIDFromBytes(k1.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, k1, v)
+}
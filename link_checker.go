@@ -0,0 +1,189 @@
+package gox
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ExtractURLs returns urls carried by v, which can be *Any, *AnyList, *Image, *Video, *Audio, *File or *WebPage
+func ExtractURLs(v interface{}) []string {
+	var urls []string
+	add := func(u string) {
+		if len(u) > 0 {
+			urls = append(urls, u)
+		}
+	}
+
+	switch val := v.(type) {
+	case *Any:
+		if val != nil {
+			return ExtractURLs(val.Val())
+		}
+	case *AnyList:
+		for i := 0; i < val.Size(); i++ {
+			urls = append(urls, ExtractURLs(val.Get(i))...)
+		}
+	case *Image:
+		if val != nil {
+			add(val.URL)
+		}
+	case *Video:
+		if val != nil {
+			add(val.URL)
+			urls = append(urls, ExtractURLs(val.Image)...)
+		}
+	case *Audio:
+		if val != nil {
+			add(val.URL)
+		}
+	case *File:
+		if val != nil {
+			add(val.URL)
+		}
+	case *WebPage:
+		if val != nil {
+			add(val.URL)
+			urls = append(urls, ExtractURLs(val.Image)...)
+		}
+	}
+	return urls
+}
+
+type LinkStatus struct {
+	URL        string
+	StatusCode int
+	Err        error
+	CheckedAt  time.Time
+}
+
+// Dead reports whether the link is unreachable or gone.
+// Unauthorized, forbidden and rate limited responses are regarded as alive.
+func (s *LinkStatus) Dead() bool {
+	if s.Err != nil {
+		return true
+	}
+
+	switch s.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	default:
+		return s.StatusCode >= http.StatusBadRequest
+	}
+}
+
+// LinkChecker checks links with HEAD requests. Requests are rate limited and results are cached.
+type LinkChecker struct {
+	client   *http.Client
+	interval time.Duration
+	cacheTTL time.Duration
+
+	mu      sync.Mutex
+	nextAt  time.Time
+	results map[string]*LinkStatus
+}
+
+// NewLinkChecker creates a checker sending at most qps requests per second, caching results for cacheTTL.
+// If client is nil, http.DefaultClient is used.
+func NewLinkChecker(client *http.Client, qps int, cacheTTL time.Duration) *LinkChecker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if qps <= 0 {
+		panic("qps should be positive")
+	}
+
+	return &LinkChecker{
+		client:   client,
+		interval: time.Second / time.Duration(qps),
+		cacheTTL: cacheTTL,
+		results:  make(map[string]*LinkStatus),
+	}
+}
+
+func (c *LinkChecker) Check(ctx context.Context, url string) *LinkStatus {
+	c.mu.Lock()
+	s := c.results[url]
+	c.mu.Unlock()
+	if s != nil && time.Since(s.CheckedAt) < c.cacheTTL {
+		return s
+	}
+
+	s = &LinkStatus{URL: url}
+	if err := c.wait(ctx); err != nil {
+		s.Err = err
+		return s
+	}
+
+	s.StatusCode, s.Err = c.do(ctx, http.MethodHead, url)
+	if s.StatusCode == http.StatusMethodNotAllowed || s.StatusCode == http.StatusNotImplemented {
+		s.StatusCode, s.Err = c.do(ctx, http.MethodGet, url)
+	}
+	s.CheckedAt = time.Now()
+
+	if ctx.Err() == nil {
+		c.mu.Lock()
+		c.results[url] = s
+		c.mu.Unlock()
+	}
+	return s
+}
+
+// Sweep checks all links in items, and calls report with the item and its dead links
+func (c *LinkChecker) Sweep(ctx context.Context, items []*Any, report func(item *Any, dead []*LinkStatus)) error {
+	for _, item := range items {
+		var dead []*LinkStatus
+		for _, u := range ExtractURLs(item) {
+			if s := c.Check(ctx, u); s.Dead() {
+				dead = append(dead, s)
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if len(dead) > 0 && report != nil {
+			report(item, dead)
+		}
+	}
+	return nil
+}
+
+func (c *LinkChecker) wait(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	at := c.nextAt
+	if at.Before(now) {
+		at = now
+	}
+	c.nextAt = at.Add(c.interval)
+	c.mu.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (c *LinkChecker) do(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,36 @@
+package gox_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkChecker_Sweep(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/dead.png" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	alive := gox.NewAny(&gox.Image{URL: server.URL + "/alive.png"})
+	dead := gox.NewAny(&gox.Video{URL: server.URL + "/alive.mp4", Image: &gox.Image{URL: server.URL + "/dead.png"}})
+	c := gox.NewLinkChecker(server.Client(), 100, time.Minute)
+	var reported []*gox.Any
+	err := c.Sweep(context.Background(), []*gox.Any{alive, dead, dead}, func(item *gox.Any, links []*gox.LinkStatus) {
+		reported = append(reported, item)
+		assert.Len(t, links, 1)
+		assert.Equal(t, http.StatusNotFound, links[0].StatusCode)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*gox.Any{dead, dead}, reported)
+	assert.Equal(t, 3, hits)
+}
@@ -0,0 +1,180 @@
+package gox
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc fetches values of ids. IDs absent in the result are loaded as ErrNoValue.
+// ctx carries values of the caller starting the batch, but not its deadline or cancellation, as the batch is shared
+// by other callers. BatchFunc should apply its own timeout.
+type BatchFunc func(ctx context.Context, ids []ID) (map[ID]interface{}, error)
+
+type loaderResult struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+type loaderBatch struct {
+	ctx     context.Context
+	ids     []ID
+	results []*loaderResult
+	timer   *time.Timer
+}
+
+// Loader coalesces concurrent loads of IDs into batched fetches, and caches results.
+// It's intended to be created per request, e.g. for resolving fields of a GraphQL query.
+type Loader struct {
+	fetch    BatchFunc
+	maxBatch int
+	wait     time.Duration
+
+	mu    sync.Mutex
+	cache map[ID]*loaderResult
+	batch *loaderBatch
+}
+
+// NewLoader creates a loader which calls fetch with at most maxBatch IDs, after waiting up to wait for more IDs.
+// maxBatch <= 0 means no limit.
+func NewLoader(fetch BatchFunc, maxBatch int, wait time.Duration) *Loader {
+	if fetch == nil {
+		panic("fetch is nil")
+	}
+	return &Loader{
+		fetch:    fetch,
+		maxBatch: maxBatch,
+		wait:     wait,
+		cache:    make(map[ID]*loaderResult),
+	}
+}
+
+func (l *Loader) Load(ctx context.Context, id ID) (interface{}, error) {
+	r := l.enqueue(ctx, id)
+	select {
+	case <-r.done:
+		return r.val, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LoadMany loads ids in one batch if possible. Values and errors are in the order of ids.
+func (l *Loader) LoadMany(ctx context.Context, ids []ID) ([]interface{}, []error) {
+	results := make([]*loaderResult, len(ids))
+	for i, id := range ids {
+		results[i] = l.enqueue(ctx, id)
+	}
+
+	values := make([]interface{}, len(ids))
+	errs := make([]error, len(ids))
+	for i, r := range results {
+		select {
+		case <-r.done:
+			values[i], errs[i] = r.val, r.err
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	return values, errs
+}
+
+// Prime puts value of id into cache if it's absent
+func (l *Loader) Prime(id ID, v interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.cache[id]; !ok {
+		r := &loaderResult{done: make(chan struct{}), val: v}
+		close(r.done)
+		l.cache[id] = r
+	}
+}
+
+// Clear removes cached value of id, e.g. after it's updated
+func (l *Loader) Clear(id ID) {
+	l.mu.Lock()
+	delete(l.cache, id)
+	l.mu.Unlock()
+}
+
+func (l *Loader) enqueue(ctx context.Context, id ID) *loaderResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if r, ok := l.cache[id]; ok {
+		return r
+	}
+
+	r := &loaderResult{done: make(chan struct{})}
+	l.cache[id] = r
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch{ctx: detachedContext{ctx}}
+		l.batch = b
+		b.timer = time.AfterFunc(l.wait, func() {
+			l.mu.Lock()
+			if l.batch == b {
+				l.batch = nil
+			}
+			l.mu.Unlock()
+			l.dispatch(b)
+		})
+	}
+	b.ids = append(b.ids, id)
+	b.results = append(b.results, r)
+
+	if l.maxBatch > 0 && len(b.ids) >= l.maxBatch {
+		l.batch = nil
+		if b.timer.Stop() {
+			go l.dispatch(b)
+		}
+	}
+	return r
+}
+
+func (l *Loader) dispatch(b *loaderBatch) {
+	values, err := l.fetch(b.ctx, b.ids)
+	if err != nil {
+		// failures are not cached, so that later loads can retry
+		l.mu.Lock()
+		for i, id := range b.ids {
+			if l.cache[id] == b.results[i] {
+				delete(l.cache, id)
+			}
+		}
+		l.mu.Unlock()
+	}
+
+	for i, id := range b.ids {
+		r := b.results[i]
+		if err != nil {
+			r.err = err
+		} else if v, ok := values[id]; ok {
+			r.val = v
+		} else {
+			r.err = ErrNoValue
+		}
+		close(r.done)
+	}
+}
+
+// detachedContext keeps values of parent, but is never canceled
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
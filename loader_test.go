@@ -0,0 +1,114 @@
+package gox_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]gox.ID
+	fail := false
+	fetch := func(ctx context.Context, ids []gox.ID) (map[gox.ID]interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, ids)
+		if fail {
+			return nil, errors.New("fetch error")
+		}
+
+		m := make(map[gox.ID]interface{}, len(ids))
+		for _, id := range ids {
+			if id != 0 {
+				m[id] = int64(id) * 10
+			}
+		}
+		return m, nil
+	}
+
+	ctx := context.Background()
+	l := gox.NewLoader(fetch, 3, 10*time.Millisecond)
+	var wg sync.WaitGroup
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(id gox.ID) {
+			defer wg.Done()
+			v, err := l.Load(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(id)*10, v)
+		}(gox.ID(i))
+	}
+	wg.Wait()
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], 3)
+	assert.Len(t, batches[1], 2)
+
+	values, errs := l.LoadMany(ctx, []gox.ID{1, 0, 6})
+	assert.Equal(t, []interface{}{int64(10), nil, int64(60)}, values)
+	assert.Equal(t, []error{nil, gox.ErrNoValue, nil}, errs)
+	require.Len(t, batches, 3)
+	assert.Equal(t, []gox.ID{0, 6}, batches[2])
+
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	_, err := l.Load(ctx, 7)
+	assert.Error(t, err)
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	v, err := l.Load(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(70), v)
+}
+
+type loaderCtxKey struct{}
+
+func TestLoader_CanceledCaller(t *testing.T) {
+	release := make(chan struct{})
+	fetch := func(ctx context.Context, ids []gox.ID) (map[gox.ID]interface{}, error) {
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		m := make(map[gox.ID]interface{}, len(ids))
+		for _, id := range ids {
+			m[id] = ctx.Value(loaderCtxKey{})
+		}
+		return m, nil
+	}
+
+	l := gox.NewLoader(fetch, 0, 50*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), loaderCtxKey{}, "v"))
+	done := make(chan error, 1)
+	go func() {
+		_, err := l.Load(ctx, 1)
+		done <- err
+	}()
+	time.Sleep(2 * time.Millisecond)
+
+	// the other caller joins the batch started by the canceled one
+	var v interface{}
+	var err error
+	loaded := make(chan struct{})
+	go func() {
+		v, err = l.Load(context.Background(), 2)
+		close(loaded)
+	}()
+	time.Sleep(2 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	close(release)
+	<-loaded
+	require.NoError(t, err)
+	assert.Equal(t, "v", v)
+}
@@ -0,0 +1,94 @@
+package gox
+
+import (
+	"strings"
+	"sync"
+)
+
+// Lang is a BCP 47 language tag, e.g. en, zh-Hans-CN
+type Lang string
+
+// Fallbacks returns lang and its less specific tags, e.g. zh-Hans-CN, zh-Hans, zh
+func (l Lang) Fallbacks() []Lang {
+	s := strings.Replace(string(l), "_", "-", -1)
+	var langs []Lang
+	for len(s) > 0 {
+		langs = append(langs, Lang(s))
+		i := strings.LastIndexByte(s, '-')
+		if i < 0 {
+			break
+		}
+		s = s[:i]
+	}
+	return langs
+}
+
+// LocalizedString is a string in multiple languages
+type LocalizedString map[Lang]string
+
+// Get returns the string of lang or its fallbacks, then the string of DefaultLang, and finally any available string
+func (s LocalizedString) Get(lang Lang) string {
+	for _, l := range lang.Fallbacks() {
+		if v, ok := s[l]; ok {
+			return v
+		}
+	}
+
+	if v, ok := s[DefaultLang]; ok {
+		return v
+	}
+
+	// pick the smallest tag to be deterministic
+	var min Lang
+	for l := range s {
+		if min == "" || l < min {
+			min = l
+		}
+	}
+	return s[min]
+}
+
+var DefaultLang Lang = "en"
+
+// Localizer returns a copy of v resolved in lang. Returning nil drops the item.
+type Localizer func(v interface{}, lang Lang) interface{}
+
+var localizers struct {
+	sync.RWMutex
+	m map[string]Localizer
+}
+
+// RegisterLocalizer sets localizer for values of Any type typeName
+func RegisterLocalizer(typeName string, f Localizer) {
+	localizers.Lock()
+	defer localizers.Unlock()
+	if localizers.m == nil {
+		localizers.m = make(map[string]Localizer)
+	}
+	localizers.m[typeName] = f
+}
+
+// LocalizeList returns a new list whose items are resolved in lang by registered localizers.
+// Items without a localizer are kept as they are.
+func LocalizeList(list *AnyList, lang Lang) *AnyList {
+	result := NewAnyList()
+	localizers.RLock()
+	defer localizers.RUnlock()
+	for i := 0; i < list.Size(); i++ {
+		a := list.Get(i)
+		if a == nil {
+			continue
+		}
+
+		f := localizers.m[a.TypeName()]
+		if f == nil {
+			result.Append(a)
+			continue
+		}
+
+		if v := f(a.Val(), lang); v != nil {
+			result.Append(NewAny(v))
+		}
+	}
+	return result
+}
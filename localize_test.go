@@ -0,0 +1,45 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type localizedArticle struct {
+	Title gox.LocalizedString `json:"title"`
+	URL   string              `json:"url"`
+}
+
+func TestLang_Fallbacks(t *testing.T) {
+	assert.Equal(t, []gox.Lang{"zh-Hans-CN", "zh-Hans", "zh"}, gox.Lang("zh_Hans-CN").Fallbacks())
+	assert.Equal(t, []gox.Lang{"en"}, gox.Lang("en").Fallbacks())
+}
+
+func TestLocalizeList(t *testing.T) {
+	s := gox.LocalizedString{"en": "Hello", "zh": "你好", "fr": "Bonjour"}
+	assert.Equal(t, "你好", s.Get("zh-Hans-CN"))
+	assert.Equal(t, "Hello", s.Get("ja"))
+	assert.Equal(t, "Bonjour", gox.LocalizedString{"fr": "Bonjour", "ja": "こんにちは"}.Get("de"))
+
+	gox.RegisterLocalizer("localized_article", func(v interface{}, lang gox.Lang) interface{} {
+		a := v.(*localizedArticle)
+		if len(a.Title) == 0 {
+			return nil
+		}
+		return &gox.WebPage{Title: a.Title.Get(lang), URL: a.URL}
+	})
+
+	l := gox.NewAnyList(
+		gox.NewAny(&localizedArticle{Title: s, URL: "https://a.com"}),
+		gox.NewAny(&localizedArticle{URL: "https://b.com"}),
+		gox.NewAny("text"),
+	)
+	result := gox.LocalizeList(l, "zh-CN")
+	require.Equal(t, 2, result.Size())
+	assert.Equal(t, "你好", result.Get(0).WebPage().Title)
+	assert.Equal(t, "text", result.Get(1).Text())
+	assert.Equal(t, 3, l.Size())
+}
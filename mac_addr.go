@@ -0,0 +1,122 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// MACAddr is a 48-bit mac address in lower case separated by colons, e.g. 00:1a:2b:3c:4d:5e
+type MACAddr string
+
+var _ driver.Valuer = MACAddr("")
+var _ sql.Scanner = (*MACAddr)(nil)
+
+// ParseMACAddr parses s separated by colons (00:1A:2B:3C:4D:5E), dashes (00-1A-2B-3C-4D-5E),
+// dots (001a.2b3c.4d5e), or without separators (001A2B3C4D5E), and normalizes it
+func ParseMACAddr(s string) (MACAddr, error) {
+	s = strings.TrimSpace(s)
+	var b []byte
+	var err error
+	if len(s) == 12 {
+		b, err = hex.DecodeString(s)
+	} else {
+		b, err = net.ParseMAC(s)
+	}
+
+	if err != nil || len(b) != 6 {
+		return "", fmt.Errorf("invalid mac address: %s", s)
+	}
+	return MACAddr(net.HardwareAddr(b).String()), nil
+}
+
+// MACAddrFrom converts hardware address of a net.Interface, or returns empty string if it isn't 48-bit
+func MACAddrFrom(addr net.HardwareAddr) MACAddr {
+	if len(addr) != 6 {
+		return ""
+	}
+	return MACAddr(addr.String())
+}
+
+func (m MACAddr) IsValid() bool {
+	v, err := ParseMACAddr(string(m))
+	return err == nil && v == m
+}
+
+// OUI returns the organizationally unique identifier which identifies the vendor, e.g. 00:1a:2b
+func (m MACAddr) OUI() string {
+	if !m.IsValid() {
+		return ""
+	}
+	return string(m[:8])
+}
+
+// IsLocal reports whether m is locally administered rather than assigned by a vendor, e.g. addresses of VMs and containers
+func (m MACAddr) IsLocal() bool {
+	b := m.HardwareAddr()
+	return b != nil && b[0]&0x02 != 0
+}
+
+// HardwareAddr returns m in bytes, or nil if m is invalid
+func (m MACAddr) HardwareAddr() net.HardwareAddr {
+	if !m.IsValid() {
+		return nil
+	}
+	b, _ := net.ParseMAC(string(m))
+	return b
+}
+
+func (m MACAddr) String() string {
+	return string(m)
+}
+
+func (m *MACAddr) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if len(s) == 0 {
+		*m = ""
+		return nil
+	}
+
+	v, err := ParseMACAddr(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan accepts text in any format of ParseMACAddr, and 6 bytes in binary
+func (m *MACAddr) Scan(src interface{}) error {
+	if b, ok := src.([]byte); ok && len(b) == 6 {
+		*m = MACAddrFrom(b)
+		return nil
+	}
+
+	s, err := scanCodeString(src)
+	if err != nil || len(s) == 0 {
+		*m = ""
+		return err
+	}
+
+	v, err := ParseMACAddr(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+func (m MACAddr) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return string(m), nil
+}
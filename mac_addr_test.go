@@ -0,0 +1,46 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMACAddr(t *testing.T) {
+	for _, s := range []string{"00:1A:2B:3C:4D:5E", "00-1a-2b-3c-4d-5e", "001a.2b3c.4d5e", "001A2B3C4D5E"} {
+		m, err := gox.ParseMACAddr(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, gox.MACAddr("00:1a:2b:3c:4d:5e"), m)
+		assert.Equal(t, "00:1a:2b:3c:4d:5e", m.String())
+	}
+
+	m, _ := gox.ParseMACAddr("00:1a:2b:3c:4d:5e")
+	assert.Equal(t, "00:1a:2b", m.OUI())
+	assert.False(t, m.IsLocal())
+	assert.True(t, gox.MACAddr("02:42:ac:11:00:02").IsLocal())
+	assert.Len(t, m.HardwareAddr(), 6)
+
+	_, err := gox.ParseMACAddr("00:1a:2b:3c:4d")
+	assert.Error(t, err)
+	_, err = gox.ParseMACAddr("00:00:5e:00:53:01:02:03")
+	assert.Error(t, err)
+	assert.False(t, gox.MACAddr("00:1A:2B:3C:4D:5E").IsValid())
+}
+
+func TestMACAddr_JSON(t *testing.T) {
+	var v struct {
+		MAC gox.MACAddr `json:"mac"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"mac":"00-1A-2B-3C-4D-5E"}`), &v))
+	assert.Equal(t, gox.MACAddr("00:1a:2b:3c:4d:5e"), v.MAC)
+	assert.Error(t, json.Unmarshal([]byte(`{"mac":"xyz"}`), &v))
+
+	var m gox.MACAddr
+	require.NoError(t, m.Scan([]byte{0, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}))
+	assert.Equal(t, v.MAC, m)
+	require.NoError(t, m.Scan("001a.2b3c.4d5e"))
+	assert.Equal(t, v.MAC, m)
+}
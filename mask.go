@@ -0,0 +1,61 @@
+package gox
+
+import "strings"
+
+// MaskChar replaces hidden characters of Mask
+const MaskChar = '*'
+
+// Mask keeps keepPrefix leading and keepSuffix trailing runes of s and replaces the rest with MaskChar,
+// e.g. Mask("13812345678", 3, 4) returns 138****5678.
+// All runes are masked if s isn't longer than keepPrefix+keepSuffix, so that short secrets are never revealed.
+func Mask(s string, keepPrefix, keepSuffix int) string {
+	r := []rune(s)
+	if keepPrefix < 0 {
+		keepPrefix = 0
+	}
+
+	if keepSuffix < 0 {
+		keepSuffix = 0
+	}
+
+	if len(r) <= keepPrefix+keepSuffix {
+		keepPrefix, keepSuffix = 0, 0
+	}
+
+	for i := keepPrefix; i < len(r)-keepSuffix; i++ {
+		r[i] = MaskChar
+	}
+	return string(r)
+}
+
+// MaskPhone keeps the country code, 3 leading and 4 trailing digits of a phone number, e.g. +86 138****5678
+func MaskPhone(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); strings.HasPrefix(s, "+") && i > 0 {
+		return s[:i+1] + MaskPhone(s[i+1:])
+	}
+	return Mask(s, 3, 4)
+}
+
+// MaskEmail keeps the first and last characters of the local part and the domain, e.g. j******e@example.com
+func MaskEmail(s string) string {
+	i := strings.LastIndexByte(s, '@')
+	if i < 0 {
+		return Mask(s, 1, 1)
+	}
+
+	local := []rune(s[:i])
+	switch len(local) {
+	case 0:
+		return s
+	case 1, 2:
+		return string(local[0]) + strings.Repeat(string(MaskChar), len(local)-1) + s[i:]
+	default:
+		return Mask(string(local), 1, 1) + s[i:]
+	}
+}
+
+// MaskIDCard keeps 4 leading and 4 trailing characters of an identity card or passport number, e.g. 1101**********1234
+func MaskIDCard(s string) string {
+	return Mask(strings.TrimSpace(s), 4, 4)
+}
@@ -0,0 +1,25 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "138****5678", gox.Mask("13812345678", 3, 4))
+	assert.Equal(t, "张*", gox.Mask("张三", 1, 0))
+	assert.Equal(t, "****", gox.Mask("1234", 2, 2))
+	assert.Equal(t, "", gox.Mask("", 1, 1))
+
+	assert.Equal(t, "138****5678", gox.MaskPhone("13812345678"))
+	assert.Equal(t, "+86 138****5678", gox.MaskPhone("+86 13812345678"))
+	assert.Equal(t, "+14*****2671", gox.MaskPhone("+14155552671"))
+
+	assert.Equal(t, "j******e@example.com", gox.MaskEmail("john.doe@example.com"))
+	assert.Equal(t, "a*@example.com", gox.MaskEmail("ab@example.com"))
+	assert.Equal(t, "a@example.com", gox.MaskEmail("a@example.com"))
+
+	assert.Equal(t, "1101**********1234", gox.MaskIDCard("110101199001011234"))
+}
@@ -1,5 +1,11 @@
 package gox
 
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
 type Namer interface {
 	Name(srcName string) (dstName string)
 }
@@ -30,25 +36,9 @@ var EqualNamer NameFunc = func(srcName string) (dstName string) {
 
 var DefaultNamer Namer = EqualNamer
 
+// CamelToSnake converts s to snake case, segmenting acronyms and digits, e.g. HTTPServerV2 to http_server_v2
 func CamelToSnake(s string) string {
-	snake := make([]rune, 0, len(s)+1)
-	flag := false
-	k := 'a' - 'A'
-	for i, c := range s {
-		if c >= 'A' && c <= 'Z' {
-			if !flag {
-				flag = true
-				if i > 0 {
-					snake = append(snake, '_')
-				}
-			}
-			snake = append(snake, c+k)
-		} else {
-			flag = false
-			snake = append(snake, c)
-		}
-	}
-	return string(snake)
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
 }
 
 func SnakeToCamel(s string) string {
@@ -72,3 +62,175 @@ func SnakeToCamel(s string) string {
 	}
 	return string(camel)
 }
+
+// ToCamelCase converts s in any case to lower camel case, e.g. hello_world to helloWorld
+func ToCamelCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = capitalize(w)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// ToPascalCase converts s in any case to upper camel case, e.g. hello_world to HelloWorld
+func ToPascalCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, "")
+}
+
+// ToKebabCase converts s in any case to kebab case, e.g. helloWorld to hello-world
+func ToKebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// ToScreamingSnake converts s in any case to upper snake case, e.g. helloWorld to HELLO_WORLD
+func ToScreamingSnake(s string) string {
+	return strings.ToUpper(strings.Join(splitWords(s), "_"))
+}
+
+var acronyms = struct {
+	sync.RWMutex
+	words map[string]bool
+}{
+	words: map[string]bool{
+		"API": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+		"JSON": true, "OS": true, "SQL": true, "TCP": true, "UDP": true, "UI": true, "URL": true, "UUID": true, "XML": true,
+	},
+}
+
+// RegisterAcronyms adds words which are upper case in ToCamelCase and ToPascalCase, e.g. user_id to UserID.
+// Default acronyms include ID, URL, HTTP, JSON, API, etc.
+func RegisterAcronyms(words ...string) {
+	acronyms.Lock()
+	for _, w := range words {
+		acronyms.words[strings.ToUpper(w)] = true
+	}
+	acronyms.Unlock()
+}
+
+func isAcronym(w string) bool {
+	acronyms.RLock()
+	ok := acronyms.words[strings.ToUpper(w)]
+	acronyms.RUnlock()
+	return ok
+}
+
+func capitalize(w string) string {
+	if isAcronym(w) {
+		return strings.ToUpper(w)
+	}
+
+	// plural of acronym, e.g. IDs
+	if n := len(w) - 1; n > 0 && (w[n] == 's' || w[n] == 'S') && isAcronym(w[:n]) {
+		return strings.ToUpper(w[:n]) + "s"
+	}
+	w = strings.ToLower(w)
+	for i, c := range w {
+		return string(unicode.ToUpper(c)) + w[i+len(string(c)):]
+	}
+	return w
+}
+
+func isWordSeparator(c rune) bool {
+	return c == '_' || c == '-' || c == '.' || unicode.IsSpace(c)
+}
+
+// splitWords splits s in camel, pascal, snake or kebab case into words. A word starts after a separator,
+// at an upper case letter following a lower case letter or digit, or at the last upper case letter of an acronym followed by lower case letters.
+// Digits stick to the preceding letters, e.g. HTTPServerV2 is split into HTTP, Server and V2.
+// Registered acronyms keep a trailing plural s, e.g. UserIDs is split into User and IDs,
+// and adjacent acronyms are separated, e.g. JSONAPI is split into JSON and API.
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		if isWordSeparator(c) {
+			flush()
+			i++
+			continue
+		}
+
+		if !unicode.IsUpper(c) {
+			word = append(word, c)
+			i++
+			continue
+		}
+
+		flush()
+		j := i
+		for j < len(runes) && unicode.IsUpper(runes[j]) {
+			j++
+		}
+
+		if j == len(runes) || !unicode.IsLower(runes[j]) {
+			// the run ends the word, digits may follow it, e.g. SHA256
+			parts := splitAcronyms(runes[i:j])
+			words = append(words, parts[:len(parts)-1]...)
+			word = append(word, []rune(parts[len(parts)-1])...)
+			i = j
+			continue
+		}
+
+		if runes[j] == 's' && (j+1 == len(runes) || !unicode.IsLower(runes[j+1])) {
+			// plural of acronyms, e.g. IDs and URLs
+			if parts := matchAcronyms(runes[i:j]); parts != nil {
+				parts[len(parts)-1] += "s"
+				words = append(words, parts...)
+				i = j + 1
+				continue
+			}
+		}
+
+		// the last upper case letter starts a new word, e.g. Server in HTTPServer
+		if j-1 > i {
+			words = append(words, splitAcronyms(runes[i:j-1])...)
+		}
+		word = append(word, runes[j-1])
+		i = j
+	}
+	flush()
+	return words
+}
+
+// splitAcronyms splits run of upper case letters if it consists of registered acronyms, e.g. JSONAPI to JSON and API
+func splitAcronyms(run []rune) []string {
+	if parts := matchAcronyms(run); parts != nil {
+		return parts
+	}
+	return []string{string(run)}
+}
+
+// matchAcronyms returns acronyms covering run completely, preferring longer ones, or nil
+func matchAcronyms(run []rune) []string {
+	if len(run) == 0 {
+		return []string{}
+	}
+
+	for k := len(run); k >= 2; k-- {
+		w := string(run[:k])
+		if !isAcronym(w) {
+			continue
+		}
+
+		if rest := matchAcronyms(run[k:]); rest != nil {
+			return append([]string{w}, rest...)
+		}
+	}
+	return nil
+}
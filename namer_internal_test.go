@@ -0,0 +1,70 @@
+package gox
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRegisterAcronyms(t *testing.T) {
+	defer func() {
+		acronyms.Lock()
+		delete(acronyms.words, "OAUTH")
+		acronyms.Unlock()
+	}()
+
+	if s := ToPascalCase("oauth_token"); s != "OauthToken" {
+		t.Fatal(s)
+	}
+
+	RegisterAcronyms("oauth")
+	if s := ToPascalCase("oauth_token"); s != "OAUTHToken" {
+		t.Fatal(s)
+	}
+
+	if s := CamelToSnake("OAUTHToken"); s != "oauth_token" {
+		t.Fatal(s)
+	}
+
+	if s := CamelToSnake("OAUTHIDs"); s != "oauth_ids" {
+		t.Fatal(s)
+	}
+}
+
+type HTTPServerInfo struct {
+	Host string `json:"host"`
+}
+
+func TestAnyLegacyTypeName(t *testing.T) {
+	MustRegisterAny(&HTTPServerInfo{})
+	if name := GetAnyTypeName(&HTTPServerInfo{}); name != "http_server_info" {
+		t.Fatal(name)
+	}
+
+	var a Any
+	if err := json.Unmarshal([]byte(`{"@t":"httpserver_info","host":"a.com"}`), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := a.Val().(*HTTPServerInfo); !ok || v.Host != "a.com" {
+		t.Fatal(a.Val())
+	}
+
+	b, err := json.Marshal(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// decoded payloads are encoded the same
+	if string(b) != `{"@t":"httpserver_info","host":"a.com"}` {
+		t.Fatal(string(b))
+	}
+
+	a.SetVal(&HTTPServerInfo{Host: "b.com"})
+	if b, err = json.Marshal(&a); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"@t":"http_server_info","host":"b.com"}` {
+		t.Fatal(string(b))
+	}
+}
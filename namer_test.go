@@ -53,9 +53,84 @@ func TestCamelToSnake(t *testing.T) {
 			Input:  "helloWorldID",
 			Output: "hello_world_id",
 		},
+		{
+			Input:  "HTTPServerV2",
+			Output: "http_server_v2",
+		},
+		{
+			Input:  "userIDList",
+			Output: "user_id_list",
+		},
+		{
+			Input:  "SHA256Sum",
+			Output: "sha256_sum",
+		},
+		{
+			Input:  "ÉtatCivil",
+			Output: "état_civil",
+		},
+		{
+			Input:  "userIDs",
+			Output: "user_ids",
+		},
+		{
+			Input:  "UserIDs",
+			Output: "user_ids",
+		},
+		{
+			Input:  "ImageURLs",
+			Output: "image_urls",
+		},
+		{
+			Input:  "userIDsByName",
+			Output: "user_ids_by_name",
+		},
+		{
+			Input:  "JSONAPIs",
+			Output: "json_apis",
+		},
+		{
+			Input:  "JSONAPIServer",
+			Output: "json_api_server",
+		},
+		{
+			Input:  "HOSTName",
+			Output: "host_name",
+		},
 	}
 
 	for _, tc := range tests {
 		assert.Equal(t, tc.Output, gox.CamelToSnake(tc.Input))
 	}
 }
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		Input     string
+		Camel     string
+		Pascal    string
+		Kebab     string
+		Screaming string
+	}{
+		{"hello", "hello", "Hello", "hello", "HELLO"},
+		{"hello_world", "helloWorld", "HelloWorld", "hello-world", "HELLO_WORLD"},
+		{"helloWorld", "helloWorld", "HelloWorld", "hello-world", "HELLO_WORLD"},
+		{"HelloWorld", "helloWorld", "HelloWorld", "hello-world", "HELLO_WORLD"},
+		{"hello-world", "helloWorld", "HelloWorld", "hello-world", "HELLO_WORLD"},
+		{"HELLO_WORLD", "helloWorld", "HelloWorld", "hello-world", "HELLO_WORLD"},
+		{" hello  world ", "helloWorld", "HelloWorld", "hello-world", "HELLO_WORLD"},
+		{"élanVital", "élanVital", "ÉlanVital", "élan-vital", "ÉLAN_VITAL"},
+		{"user_id", "userID", "UserID", "user-id", "USER_ID"},
+		{"HTTPServerV2", "httpServerV2", "HTTPServerV2", "http-server-v2", "HTTP_SERVER_V2"},
+		{"user_ids", "userIDs", "UserIDs", "user-ids", "USER_IDS"},
+		{"ImageURLs", "imageURLs", "ImageURLs", "image-urls", "IMAGE_URLS"},
+		{"", "", "", "", ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.Camel, gox.ToCamelCase(tc.Input), tc.Input)
+		assert.Equal(t, tc.Pascal, gox.ToPascalCase(tc.Input), tc.Input)
+		assert.Equal(t, tc.Kebab, gox.ToKebabCase(tc.Input), tc.Input)
+		assert.Equal(t, tc.Screaming, gox.ToScreamingSnake(tc.Input), tc.Input)
+	}
+}
@@ -1,8 +1,13 @@
 package gox
 
 import (
+	"context"
+	"fmt"
 	"github.com/gopub/log"
 	"net"
+	"path"
+	"sort"
+	"time"
 )
 
 // GetIP gets ip address such as "10.0.0.1"
@@ -61,12 +66,79 @@ func GetIP() net.IP {
 	return localAddr.IP
 }
 
-func GetMacAddrs() ([]string, error) {
+// InterfaceFilter reports whether an interface is accepted
+type InterfaceFilter func(ifa net.Interface) bool
+
+// VirtualInterfacePatterns matches names of docker bridges, veth pairs, VPN tunnels and VM adapters
+var VirtualInterfacePatterns = []string{
+	"docker*", "veth*", "br-*", "virbr*", "cni*", "flannel*", "cali*", "kube-*",
+	"vmnet*", "vboxnet*", "vEthernet*", "tun*", "tap*", "utun*", "wg*", "zt*", "awdl*", "llw*", "bridge*",
+}
+
+// ExcludeInterfaceFlags rejects interfaces with any of flags, e.g. net.FlagLoopback|net.FlagPointToPoint
+func ExcludeInterfaceFlags(flags net.Flags) InterfaceFilter {
+	return func(ifa net.Interface) bool {
+		return ifa.Flags&flags == 0
+	}
+}
+
+// ExcludeInterfaceNames rejects interfaces whose names match any of patterns in the syntax of path.Match
+func ExcludeInterfaceNames(patterns ...string) InterfaceFilter {
+	return func(ifa net.Interface) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, ifa.Name); ok {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// PhysicalInterface accepts interfaces which are up, have hardware addresses,
+// aren't loopback or point-to-point, and don't match VirtualInterfacePatterns
+func PhysicalInterface(ifa net.Interface) bool {
+	return ifa.Flags&net.FlagUp != 0 &&
+		len(ifa.HardwareAddr) > 0 &&
+		ExcludeInterfaceFlags(net.FlagLoopback|net.FlagPointToPoint)(ifa) &&
+		ExcludeInterfaceNames(VirtualInterfacePatterns...)(ifa)
+}
+
+// GetInterfaces returns interfaces accepted by all filters, sorted by name
+func GetInterfaces(filters ...InterfaceFilter) ([]net.Interface, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		return nil, err
 	}
 
+	res := ifaces[:0]
+	for _, ifa := range ifaces {
+		if acceptInterface(ifa, filters) {
+			res = append(res, ifa)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+func acceptInterface(ifa net.Interface, filters []InterfaceFilter) bool {
+	for _, f := range filters {
+		if !f(ifa) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetMacAddrs returns mac addresses of interfaces accepted by all filters, sorted by interface name.
+// E.g. GetMacAddrs(PhysicalInterface) returns addresses which don't change as containers or VPNs come and go.
+func GetMacAddrs(filters ...InterfaceFilter) ([]string, error) {
+	ifaces, err := GetInterfaces(filters...)
+	if err != nil {
+		return nil, err
+	}
+
 	addrs := make([]string, len(ifaces))
 	for i, ifa := range ifaces {
 		addrs[i] = ifa.HardwareAddr.String()
@@ -74,3 +146,94 @@ func GetMacAddrs() ([]string, error) {
 
 	return addrs, nil
 }
+
+// GetIPs returns ip addresses of interfaces accepted by filter, sorted by interface name. filter can be nil to accept all.
+// E.g. GetIPs(PhysicalInterface) skips addresses of docker bridges and VPN tunnels.
+func GetIPs(filter func(ifa net.Interface) bool) ([]net.IP, error) {
+	var filters []InterfaceFilter
+	if filter != nil {
+		filters = append(filters, filter)
+	}
+
+	ifaces, err := GetInterfaces(filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	for _, ifa := range ifaces {
+		addrs, err := ifa.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range addrs {
+			if n, ok := a.(*net.IPNet); ok {
+				ips = append(ips, NormalizeIP(n.IP))
+			}
+		}
+	}
+	return ips, nil
+}
+
+// GetIPByInterface returns ip of the interface named name, e.g. eth1 of a multi-homed host.
+// IPv4 global unicast address is preferred over IPv6, and link-local addresses are the last resort.
+func GetIPByInterface(name string) (net.IP, error) {
+	ips, err := GetIPs(func(ifa net.Interface) bool {
+		return ifa.Name == name
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no ip on interface %s", name)
+	}
+
+	sort.SliceStable(ips, func(i, j int) bool {
+		return ipPreference(ips[i]) < ipPreference(ips[j])
+	})
+	return ips[0], nil
+}
+
+func ipPreference(ip net.IP) int {
+	switch {
+	case ip.IsGlobalUnicast() && ip.To4() != nil:
+		return 0
+	case ip.IsGlobalUnicast():
+		return 1
+	default:
+		return 2
+	}
+}
+
+// FreePort returns a tcp port which is free to listen on at the moment, e.g. for servers in integration tests
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// WaitForPort blocks until a tcp connection to addr succeeds, trying every interval, or until ctx is done
+func WaitForPort(ctx context.Context, addr string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+
+	var d net.Dialer
+	for {
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package gox_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhysicalInterface(t *testing.T) {
+	mac, _ := net.ParseMAC("00:1a:2b:3c:4d:5e")
+	eth := net.Interface{Name: "eth0", Flags: net.FlagUp | net.FlagBroadcast, HardwareAddr: mac}
+	assert.True(t, gox.PhysicalInterface(eth))
+
+	down := eth
+	down.Flags = 0
+	assert.False(t, gox.PhysicalInterface(down))
+
+	docker := eth
+	docker.Name = "docker0"
+	assert.False(t, gox.PhysicalInterface(docker))
+
+	lo := net.Interface{Name: "lo", Flags: net.FlagUp | net.FlagLoopback}
+	assert.False(t, gox.PhysicalInterface(lo))
+
+	assert.False(t, gox.ExcludeInterfaceNames("eth*")(eth))
+	assert.False(t, gox.ExcludeInterfaceFlags(net.FlagBroadcast)(eth))
+}
+
+func TestGetMacAddrs(t *testing.T) {
+	all, err := gox.GetMacAddrs()
+	require.NoError(t, err)
+	physical, err := gox.GetMacAddrs(gox.PhysicalInterface)
+	require.NoError(t, err)
+	assert.True(t, len(physical) <= len(all))
+}
+
+func TestWaitForPort(t *testing.T) {
+	port, err := gox.FreePort()
+	require.NoError(t, err)
+	addr := "127.0.0.1:" + strconv.Itoa(port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, gox.WaitForPort(ctx, addr, 10*time.Millisecond))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		time.Sleep(time.Second)
+		l.Close()
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, gox.WaitForPort(ctx, addr, 10*time.Millisecond))
+}
+
+func TestGetIPByInterface(t *testing.T) {
+	ifaces, err := gox.GetInterfaces(gox.ExcludeInterfaceFlags(net.FlagPointToPoint))
+	require.NoError(t, err)
+	for _, ifa := range ifaces {
+		ips, err := gox.GetIPs(func(i net.Interface) bool {
+			return i.Name == ifa.Name
+		})
+		require.NoError(t, err)
+		ip, err := gox.GetIPByInterface(ifa.Name)
+		if len(ips) == 0 {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Contains(t, ips, ip)
+	}
+
+	_, err = gox.GetIPByInterface("no-such-interface")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package gox
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordAlgorithm is the algorithm of HashPasswordWith
+type PasswordAlgorithm int
+
+const (
+	Argon2id PasswordAlgorithm = iota
+	Bcrypt
+)
+
+// Parameters of argon2id recommended by golang.org/x/crypto/argon2
+const (
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// HashPassword hashes pw with argon2id and a random salt.
+// The result encodes parameters in PHC string format, e.g. $argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash>,
+// so that parameters can be raised later without breaking CheckPassword.
+func HashPassword(pw string) (string, error) {
+	return HashPasswordWith(Argon2id, pw)
+}
+
+// HashPasswordWith hashes pw with alg. Bcrypt uses the default cost 10, and only the first 72 bytes of pw count.
+func HashPasswordWith(alg PasswordAlgorithm, pw string) (string, error) {
+	switch alg {
+	case Argon2id:
+		salt := make([]byte, argon2SaltLen)
+		randRead(salt)
+		key := argon2.IDKey([]byte(pw), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	case Bcrypt:
+		b, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unsupported password algorithm %d", alg)
+	}
+}
+
+// CheckPassword reports whether pw matches encoded, which is returned by HashPassword or HashPasswordWith
+func CheckPassword(pw, encoded string) bool {
+	if strings.HasPrefix(encoded, "$argon2id$") {
+		return checkArgon2idPassword(pw, encoded)
+	}
+
+	if strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$") {
+		return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(pw)) == nil
+	}
+	return false
+}
+
+func checkArgon2idPassword(pw, encoded string) bool {
+	// "", "argon2id", "v=19", "m=65536,t=1,p=4", salt, hash
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 6 {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(fields[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+
+	if time == 0 || threads == 0 || memory < 8*uint32(threads) {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil {
+		return false
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(fields[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	actual := argon2.IDKey([]byte(pw), salt, time, memory, threads, uint32(len(key)))
+	return SecureCompareBytes(actual, key)
+}
@@ -0,0 +1,31 @@
+package gox_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPassword(t *testing.T) {
+	encoded, err := gox.HashPassword("secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=65536,t=1,p=4$"), encoded)
+	assert.True(t, gox.CheckPassword("secret", encoded))
+	assert.False(t, gox.CheckPassword("Secret", encoded))
+
+	encoded2, err := gox.HashPassword("secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, encoded2)
+
+	encoded, err = gox.HashPasswordWith(gox.Bcrypt, "secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$2a$10$"), encoded)
+	assert.True(t, gox.CheckPassword("secret", encoded))
+	assert.False(t, gox.CheckPassword("Secret", encoded))
+
+	assert.False(t, gox.CheckPassword("secret", gox.MD5("secret")))
+	assert.False(t, gox.CheckPassword("secret", "$argon2id$v=19$m=0,t=1,p=4$c2FsdA$aGFzaA"))
+}
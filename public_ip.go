@@ -0,0 +1,286 @@
+package gox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopub/log"
+)
+
+const ErrNoPublicIP ErrorString = "no public ip"
+
+// PublicIPResolver discovers public ip of this machine
+type PublicIPResolver func(ctx context.Context) (net.IP, error)
+
+// DefaultPublicIPResolvers are tried in order by GetPublicIP
+var DefaultPublicIPResolvers = []PublicIPResolver{
+	STUNPublicIP("stun.l.google.com:19302"),
+	HTTPPublicIP("https://api.ipify.org"),
+	HTTPPublicIP("https://checkip.amazonaws.com"),
+	InterfacePublicIP,
+}
+
+// PublicIPCacheTTL is how long GetPublicIP reuses the discovered ip
+const PublicIPCacheTTL = 10 * time.Minute
+
+var cachedPublicIP struct {
+	mu        sync.Mutex
+	ip        net.IP
+	updatedAt time.Time
+}
+
+// GetPublicIP returns public ip discovered by DefaultPublicIPResolvers, which is cached for PublicIPCacheTTL.
+// It's useful behind NAT, where GetOutboundIP returns a private address.
+func GetPublicIP(ctx context.Context) (net.IP, error) {
+	c := &cachedPublicIP
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ip != nil && time.Since(c.updatedAt) < PublicIPCacheTTL {
+		return c.ip, nil
+	}
+
+	ip, err := GetPublicIPWith(ctx, DefaultPublicIPResolvers...)
+	if err != nil {
+		return nil, err
+	}
+	c.ip, c.updatedAt = ip, time.Now()
+	return ip, nil
+}
+
+// GetPublicIPWith returns the first public ip discovered by resolvers in order
+func GetPublicIPWith(ctx context.Context, resolvers ...PublicIPResolver) (net.IP, error) {
+	for _, r := range resolvers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ip, err := r(ctx)
+		if err != nil {
+			log.Warnf("Failed to resolve public ip: %v", err)
+			continue
+		}
+
+		if ip = NormalizeIP(ip); ip != nil && isPublicIP(ip) {
+			return ip, nil
+		}
+	}
+	return nil, ErrNoPublicIP
+}
+
+const (
+	stunBindingRequest  = 0x0001
+	stunBindingResponse = 0x0101
+	stunMagicCookie     = 0x2112A442
+	stunMappedAddress   = 0x0001
+	stunXORMappedAddr   = 0x0020
+)
+
+// STUNPublicIP returns a resolver sending a STUN binding request (RFC 5389) to server, e.g. stun.l.google.com:19302
+func STUNPublicIP(server string) PublicIPResolver {
+	return func(ctx context.Context) (net.IP, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, DefaultOutboundIPTimeout)
+			defer cancel()
+		}
+
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "udp", server)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		req := make([]byte, 20)
+		binary.BigEndian.PutUint16(req[0:], stunBindingRequest)
+		binary.BigEndian.PutUint32(req[4:], stunMagicCookie)
+		randRead(req[8:20])
+		if _, err = conn.Write(req); err != nil {
+			return nil, err
+		}
+
+		resp := make([]byte, 1024)
+		for {
+			n, err := conn.Read(resp)
+			if err != nil {
+				return nil, err
+			}
+
+			// ignore stray packets of other transactions
+			if n >= 20 && bytes.Equal(resp[8:20], req[8:20]) {
+				return parseSTUNResponse(resp[:n])
+			}
+		}
+	}
+}
+
+func parseSTUNResponse(b []byte) (net.IP, error) {
+	if binary.BigEndian.Uint16(b[0:]) != stunBindingResponse {
+		return nil, errors.New("invalid stun response")
+	}
+
+	n := int(binary.BigEndian.Uint16(b[2:]))
+	if 20+n > len(b) {
+		return nil, errors.New("invalid stun response length")
+	}
+
+	var mapped net.IP
+	attrs := b[20 : 20+n]
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:])
+		size := int(binary.BigEndian.Uint16(attrs[2:]))
+		if 4+size > len(attrs) {
+			break
+		}
+
+		v := attrs[4 : 4+size]
+		switch typ {
+		case stunXORMappedAddr:
+			// address is xored with magic cookie and transaction id
+			if ip := parseSTUNAddress(v, b[4:20]); ip != nil {
+				return ip, nil
+			}
+		case stunMappedAddress:
+			mapped = parseSTUNAddress(v, nil)
+		}
+		// attributes are padded to 4 bytes
+		size = (size + 3) &^ 3
+		if 4+size > len(attrs) {
+			break
+		}
+		attrs = attrs[4+size:]
+	}
+
+	if mapped != nil {
+		return mapped, nil
+	}
+	return nil, errors.New("no mapped address in stun response")
+}
+
+func parseSTUNAddress(v []byte, xorKey []byte) net.IP {
+	if len(v) < 4 {
+		return nil
+	}
+
+	var size int
+	switch v[1] {
+	case 1:
+		size = net.IPv4len
+	case 2:
+		size = net.IPv6len
+	default:
+		return nil
+	}
+
+	if len(v) < 4+size {
+		return nil
+	}
+
+	ip := make(net.IP, size)
+	for i := range ip {
+		ip[i] = v[4+i]
+		if xorKey != nil {
+			ip[i] ^= xorKey[i]
+		}
+	}
+	return ip
+}
+
+// HTTPPublicIP returns a resolver reading ip in plain text from url, e.g. https://api.ipify.org
+func HTTPPublicIP(url string) PublicIPResolver {
+	return func(ctx context.Context) (net.IP, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, DefaultOutboundIPTimeout)
+			defer cancel()
+		}
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: %s", url, resp.Status)
+		}
+
+		b, err := ioutil.ReadAll(io.LimitReader(resp.Body, 256))
+		if err != nil {
+			return nil, err
+		}
+
+		s := strings.TrimSpace(string(b))
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("%s: invalid ip %q", url, s)
+		}
+		return ip, nil
+	}
+}
+
+// InterfacePublicIP returns the first public ip assigned to interfaces, which works on hosts with public addresses
+func InterfacePublicIP(ctx context.Context) (net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && isPublicIP(n.IP) {
+			return n.IP, nil
+		}
+	}
+	return nil, ErrNoPublicIP
+}
+
+var privateIPNets = mustParseCIDRs(
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, s := range cidrs {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// isPrivateIP reports whether ip is in private ranges of RFC 1918 and RFC 4193, or the shared range of RFC 6598 used by carrier-grade NAT
+func isPrivateIP(ip net.IP) bool {
+	for _, n := range privateIPNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !isPrivateIP(ip)
+}
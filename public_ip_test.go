@@ -0,0 +1,65 @@
+package gox_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSTUNPublicIP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	go func() {
+		b := make([]byte, 1024)
+		n, addr, err := conn.ReadFrom(b)
+		if err != nil || n != 20 {
+			return
+		}
+
+		resp := make([]byte, 32)
+		binary.BigEndian.PutUint16(resp[0:], 0x0101)
+		binary.BigEndian.PutUint16(resp[2:], 12)
+		copy(resp[4:20], b[4:20])
+		binary.BigEndian.PutUint16(resp[20:], 0x0020)
+		binary.BigEndian.PutUint16(resp[22:], 8)
+		resp[25] = 1
+		ip := net.IPv4(203, 0, 113, 5).To4()
+		for i := range ip {
+			resp[28+i] = ip[i] ^ b[4+i]
+		}
+		conn.WriteTo(resp, addr)
+	}()
+
+	ip, err := gox.STUNPublicIP(conn.LocalAddr().String())(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.5", ip.String())
+}
+
+func TestGetPublicIPWith(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("198.51.100.7\n"))
+	}))
+	defer server.Close()
+
+	failed := func(ctx context.Context) (net.IP, error) {
+		return nil, errors.New("unreachable")
+	}
+	private := func(ctx context.Context) (net.IP, error) {
+		return net.ParseIP("192.168.1.2"), nil
+	}
+	ip, err := gox.GetPublicIPWith(context.Background(), failed, private, gox.HTTPPublicIP(server.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.7", ip.String())
+
+	_, err = gox.GetPublicIPWith(context.Background(), failed, private)
+	assert.Equal(t, gox.ErrNoPublicIP, err)
+}
@@ -0,0 +1,293 @@
+package gox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gopub/log"
+)
+
+const (
+	ErrQuotaExceeded      ErrorString = "quota exceeded"
+	ErrInvalidQuotaWindow ErrorString = "invalid quota window"
+)
+
+// Quota is the usage of a key in a fixed window
+type Quota struct {
+	Limit   int64         `json:"limit"`
+	Used    int64         `json:"used"`
+	Window  time.Duration `json:"window"`
+	ResetAt time.Time     `json:"reset_at"`
+}
+
+func (q *Quota) Remaining() int64 {
+	if q.Used >= q.Limit {
+		return 0
+	}
+	return q.Limit - q.Used
+}
+
+// QuotaStore accounts quota usage of keys such as tenant or user ids.
+// Windows are fixed and aligned to unix epoch.
+type QuotaStore interface {
+	// Consume adds n to the usage of key in current window.
+	// It returns ErrQuotaExceeded along with current quota if usage would exceed limit,
+	// and ErrInvalidQuotaWindow if window isn't positive.
+	Consume(ctx context.Context, key ID, n, limit int64, window time.Duration) (*Quota, error)
+
+	// Refund subtracts n from the usage of key in current window, e.g. after a failed upload
+	Refund(ctx context.Context, key ID, n int64, window time.Duration) error
+}
+
+func quotaWindowStart(now time.Time, window time.Duration) int64 {
+	return now.UnixNano() / int64(window) * int64(window)
+}
+
+func newQuota(limit, used int64, window time.Duration, windowStart int64) *Quota {
+	return &Quota{
+		Limit:   limit,
+		Used:    used,
+		Window:  window,
+		ResetAt: time.Unix(0, windowStart).Add(window),
+	}
+}
+
+type quotaUsage struct {
+	windowStart int64
+	used        int64
+}
+
+type memoryQuotaStore struct {
+	mu     sync.Mutex
+	usages map[ID]*quotaUsage
+}
+
+// NewMemoryQuotaStore returns a QuotaStore in memory, which is only suitable for a single instance
+func NewMemoryQuotaStore() QuotaStore {
+	return &memoryQuotaStore{usages: make(map[ID]*quotaUsage)}
+}
+
+func (s *memoryQuotaStore) Consume(ctx context.Context, key ID, n, limit int64, window time.Duration) (*Quota, error) {
+	if window <= 0 {
+		return nil, ErrInvalidQuotaWindow
+	}
+	start := quotaWindowStart(time.Now(), window)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usages[key]
+	if u == nil || u.windowStart != start {
+		u = &quotaUsage{windowStart: start}
+		s.usages[key] = u
+	}
+
+	if u.used+n > limit {
+		return newQuota(limit, u.used, window, start), ErrQuotaExceeded
+	}
+	u.used += n
+	return newQuota(limit, u.used, window, start), nil
+}
+
+func (s *memoryQuotaStore) Refund(ctx context.Context, key ID, n int64, window time.Duration) error {
+	if window <= 0 {
+		return ErrInvalidQuotaWindow
+	}
+	start := quotaWindowStart(time.Now(), window)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u := s.usages[key]; u != nil && u.windowStart == start {
+		u.used -= n
+		if u.used < 0 {
+			u.used = 0
+		}
+	}
+	return nil
+}
+
+type sqlQuotaStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLQuotaStore returns a QuotaStore backed by a PostgreSQL table:
+//
+//	CREATE TABLE quota_usage (
+//		key BIGINT NOT NULL,
+//		window_start BIGINT NOT NULL,
+//		used BIGINT NOT NULL,
+//		PRIMARY KEY(key, window_start)
+//	)
+//
+// Rows of past windows can be deleted periodically.
+func NewSQLQuotaStore(db *sql.DB, table string) QuotaStore {
+	return &sqlQuotaStore{db: db, table: table}
+}
+
+func (s *sqlQuotaStore) Consume(ctx context.Context, key ID, n, limit int64, window time.Duration) (*Quota, error) {
+	if window <= 0 {
+		return nil, ErrInvalidQuotaWindow
+	}
+	start := quotaWindowStart(time.Now(), window)
+	if n > limit {
+		used, err := s.used(ctx, key, start)
+		if err != nil {
+			return nil, err
+		}
+		return newQuota(limit, used, window, start), ErrQuotaExceeded
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %[1]s (key, window_start, used) VALUES ($1, $2, $3)
+ON CONFLICT (key, window_start) DO UPDATE SET used = %[1]s.used + EXCLUDED.used
+WHERE %[1]s.used + EXCLUDED.used <= $4
+RETURNING used`, s.table)
+	var used int64
+	err := s.db.QueryRowContext(ctx, query, key, start, n, limit).Scan(&used)
+	if err == sql.ErrNoRows {
+		used, err = s.used(ctx, key, start)
+		if err != nil {
+			return nil, err
+		}
+		return newQuota(limit, used, window, start), ErrQuotaExceeded
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return newQuota(limit, used, window, start), nil
+}
+
+func (s *sqlQuotaStore) Refund(ctx context.Context, key ID, n int64, window time.Duration) error {
+	if window <= 0 {
+		return ErrInvalidQuotaWindow
+	}
+	start := quotaWindowStart(time.Now(), window)
+	query := fmt.Sprintf("UPDATE %s SET used = GREATEST(used - $1, 0) WHERE key = $2 AND window_start = $3", s.table)
+	_, err := s.db.ExecContext(ctx, query, n, key, start)
+	return err
+}
+
+func (s *sqlQuotaStore) used(ctx context.Context, key ID, windowStart int64) (int64, error) {
+	query := fmt.Sprintf("SELECT used FROM %s WHERE key = $1 AND window_start = $2", s.table)
+	var used int64
+	err := s.db.QueryRowContext(ctx, query, key, windowStart).Scan(&used)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return used, err
+}
+
+const (
+	redisQuotaConsumeScript = `local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+local n = tonumber(ARGV[1])
+if used + n > tonumber(ARGV[2]) then return {0, used} end
+used = redis.call('INCRBY', KEYS[1], n)
+if used == n then redis.call('PEXPIRE', KEYS[1], ARGV[3]) end
+return {1, used}`
+	redisQuotaRefundScript = `if redis.call('EXISTS', KEYS[1]) == 0 then return 0 end
+local used = redis.call('DECRBY', KEYS[1], ARGV[1])
+if used < 0 then redis.call('INCRBY', KEYS[1], -used) end
+return 1`
+)
+
+type redisQuotaStore struct {
+	scripter RedisScripter
+	prefix   string
+}
+
+// NewRedisQuotaStore returns a QuotaStore keeping usage in keys prefix+key+":"+windowStart,
+// which expire after their windows
+func NewRedisQuotaStore(scripter RedisScripter, prefix string) QuotaStore {
+	if scripter == nil {
+		panic("scripter is nil")
+	}
+	return &redisQuotaStore{scripter: scripter, prefix: prefix}
+}
+
+func (s *redisQuotaStore) Consume(ctx context.Context, key ID, n, limit int64, window time.Duration) (*Quota, error) {
+	if window <= 0 {
+		return nil, ErrInvalidQuotaWindow
+	}
+	start := quotaWindowStart(time.Now(), window)
+	ttl := int64(window/time.Millisecond) + 1
+	v, err := s.scripter.Eval(ctx, redisQuotaConsumeScript, []string{s.key(key, start)}, n, limit, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	l, ok := v.([]interface{})
+	if !ok || len(l) != 2 {
+		return nil, fmt.Errorf("unexpected reply %v", v)
+	}
+	succeeded, ok1 := l[0].(int64)
+	used, ok2 := l[1].(int64)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("unexpected reply %v", v)
+	}
+
+	q := newQuota(limit, used, window, start)
+	if succeeded != 1 {
+		return q, ErrQuotaExceeded
+	}
+	return q, nil
+}
+
+func (s *redisQuotaStore) Refund(ctx context.Context, key ID, n int64, window time.Duration) error {
+	if window <= 0 {
+		return ErrInvalidQuotaWindow
+	}
+	start := quotaWindowStart(time.Now(), window)
+	_, err := s.scripter.Eval(ctx, redisQuotaRefundScript, []string{s.key(key, start)}, n)
+	return err
+}
+
+func (s *redisQuotaStore) key(key ID, windowStart int64) string {
+	return s.prefix + strconv.FormatInt(int64(key), 10) + ":" + strconv.FormatInt(windowStart, 10)
+}
+
+// QuotaMiddleware consumes one unit of quota per request for the key returned by keyFunc.
+// Requests without a key are passed through. Once quota is exhausted, it responds with 429 Too Many Requests.
+// Store failures are logged and the request is passed through.
+func QuotaMiddleware(store QuotaStore, limit int64, window time.Duration, keyFunc func(r *http.Request) (ID, bool)) func(http.Handler) http.Handler {
+	if window <= 0 {
+		panic("window should be positive")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := keyFunc(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			q, err := store.Consume(r.Context(), key, 1, limit, window)
+			if q != nil {
+				SetQuotaHeader(w.Header(), q)
+			}
+
+			if err == ErrQuotaExceeded {
+				if q != nil {
+					retryAfter := int64(time.Until(q.ResetAt)/time.Second) + 1
+					w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+				}
+				http.Error(w, string(ErrQuotaExceeded), http.StatusTooManyRequests)
+				return
+			}
+
+			if err != nil {
+				log.Error(err)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SetQuotaHeader sets X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+func SetQuotaHeader(h http.Header, q *Quota) {
+	h.Set("X-RateLimit-Limit", strconv.FormatInt(q.Limit, 10))
+	h.Set("X-RateLimit-Remaining", strconv.FormatInt(q.Remaining(), 10))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(q.ResetAt.Unix(), 10))
+}
@@ -0,0 +1,132 @@
+package gox_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaMiddleware(t *testing.T) {
+	store := gox.NewMemoryQuotaStore()
+	keyFunc := func(r *http.Request) (gox.ID, bool) {
+		return gox.ID(1), r.Header.Get("X-Tenant") != ""
+	}
+	h := gox.QuotaMiddleware(store, 2, time.Hour, keyFunc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/upload", nil)
+		req.Header.Set("X-Tenant", "1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+		if i == 2 {
+			assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+			assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests}, codes)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+type exceededQuotaStore struct {
+	gox.QuotaStore
+}
+
+func (s exceededQuotaStore) Consume(ctx context.Context, key gox.ID, n, limit int64, window time.Duration) (*gox.Quota, error) {
+	return nil, gox.ErrQuotaExceeded
+}
+
+func TestQuotaMiddleware_NilQuota(t *testing.T) {
+	keyFunc := func(r *http.Request) (gox.ID, bool) {
+		return gox.ID(1), true
+	}
+	h := gox.QuotaMiddleware(exceededQuotaStore{}, 2, time.Hour, keyFunc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestQuota_InvalidWindow(t *testing.T) {
+	assert.Panics(t, func() {
+		gox.QuotaMiddleware(gox.NewMemoryQuotaStore(), 1, 0, nil)
+	})
+
+	store := gox.NewMemoryQuotaStore()
+	_, err := store.Consume(context.Background(), 1, 1, 1, 0)
+	assert.Equal(t, gox.ErrInvalidQuotaWindow, err)
+	assert.Equal(t, gox.ErrInvalidQuotaWindow, store.Refund(context.Background(), 1, 1, -time.Second))
+}
+
+// fakeQuotaScripter emulates quota scripts, which are told apart by their numbers of arguments
+type fakeQuotaScripter struct {
+	values map[string]int64
+	ttls   map[string]int64
+}
+
+func (f *fakeQuotaScripter) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	used, ok := f.values[keys[0]]
+	n := args[0].(int64)
+	if len(args) == 1 {
+		if !ok {
+			return int64(0), nil
+		}
+		used -= n
+		if used < 0 {
+			used = 0
+		}
+		f.values[keys[0]] = used
+		return int64(1), nil
+	}
+
+	if used+n > args[1].(int64) {
+		return []interface{}{int64(0), used}, nil
+	}
+	f.values[keys[0]] = used + n
+	if !ok {
+		f.ttls[keys[0]] = args[2].(int64)
+	}
+	return []interface{}{int64(1), used + n}, nil
+}
+
+func TestRedisQuotaStore(t *testing.T) {
+	ctx := context.Background()
+	scripter := &fakeQuotaScripter{values: map[string]int64{}, ttls: map[string]int64{}}
+	store := gox.NewRedisQuotaStore(scripter, "quota:")
+
+	q, err := store.Consume(ctx, 1, 2, 3, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), q.Used)
+	assert.Equal(t, int64(1), q.Remaining())
+	assert.True(t, q.ResetAt.After(time.Now()))
+
+	q, err = store.Consume(ctx, 1, 2, 3, time.Hour)
+	assert.Equal(t, gox.ErrQuotaExceeded, err)
+	require.NotNil(t, q)
+	assert.Equal(t, int64(2), q.Used)
+
+	require.NoError(t, store.Refund(ctx, 1, 1, time.Hour))
+	q, err = store.Consume(ctx, 1, 2, 3, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), q.Used)
+
+	require.Len(t, scripter.ttls, 1)
+	for k, ttl := range scripter.ttls {
+		assert.Contains(t, k, "quota:1:")
+		assert.True(t, ttl > int64(time.Hour/time.Millisecond))
+	}
+}
@@ -0,0 +1,79 @@
+package gox
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"sync"
+)
+
+// randReaders buffer crypto/rand to save syscalls. Each reader is used by one goroutine at a time, so there's no lock contention.
+var randReaders = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewReaderSize(rand.Reader, 512)
+	},
+}
+
+type randSource struct{}
+
+func (randSource) Read(p []byte) (int, error) {
+	r := randReaders.Get().(*bufio.Reader)
+	n, err := io.ReadFull(r, p)
+	randReaders.Put(r)
+	return n, err
+}
+
+// RandSource returns a reader of cryptographically secure random bytes, which is safe for concurrent use.
+// It reads from a pool of buffered crypto/rand readers, and always fills the buffer unless an error occurs.
+func RandSource() io.Reader {
+	return randSource{}
+}
+
+// randRead fills b with random bytes from RandSource
+func randRead(b []byte) {
+	if _, err := RandSource().Read(b); err != nil {
+		panic(err)
+	}
+}
+
+// Token returns nBytes random bytes in url-safe base64 without padding, e.g. for session tokens and API keys.
+// Unlike UniqueID, it contains no time or machine information. nBytes should be at least 16.
+func Token(nBytes int) string {
+	b := make([]byte, nBytes)
+	randRead(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// TokenHex returns nBytes random bytes in hex
+func TokenHex(nBytes int) string {
+	b := make([]byte, nBytes)
+	randRead(b)
+	return hex.EncodeToString(b)
+}
+
+// Alphabet is a set of single-byte characters for RandomString
+type Alphabet string
+
+const (
+	Numeric      Alphabet = "0123456789"
+	Hex          Alphabet = "0123456789abcdef"
+	Alphanumeric Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	Base62       Alphabet = shortAlphabet
+	// NoLookalikes excludes characters which are easily confused such as 0/O/o and 1/l/I, e.g. for codes read by humans
+	NoLookalikes Alphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz"
+)
+
+// RandomString returns n characters chosen uniformly from alphabet with RandSource.
+// It returns an empty string if n isn't positive, and panics if alphabet is empty or longer than 256.
+func RandomString(n int, alphabet Alphabet) string {
+	if len(alphabet) == 0 {
+		panic("alphabet is empty")
+	}
+
+	if n <= 0 {
+		return ""
+	}
+	return RandomID(n, string(alphabet))
+}
@@ -0,0 +1,18 @@
+package gox
+
+import "context"
+
+// RedisScripter runs a lua script, which can be adapted from any redis client, e.g. with go-redis:
+//
+//	func (s *scripter) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		v, err := s.client.Eval(script, keys, args...).Result()
+//		if err == redis.Nil {
+//			return nil, nil
+//		}
+//		return v, err
+//	}
+//
+// A nil reply should be returned as nil value and nil error, rather than an error such as redis.Nil.
+type RedisScripter interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
@@ -0,0 +1,86 @@
+package gox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingKeyPolicy decides what RenderWith does with placeholders whose keys aren't in vars
+type MissingKeyPolicy int
+
+const (
+	// MissingKeyKeep leaves placeholders as they are, e.g. {name}
+	MissingKeyKeep MissingKeyPolicy = iota
+	// MissingKeyEmpty replaces placeholders with empty strings
+	MissingKeyEmpty
+	// MissingKeyError fails rendering with the missing key
+	MissingKeyError
+)
+
+// Render replaces {name} placeholders in tmpl with values in vars, e.g. Render("Hi {user.name}", M{"user": M{"name": "Tom"}}).
+// Dotted names look up nested maps. {{ and }} are literal braces. Placeholders of missing keys are kept.
+func Render(tmpl string, vars map[string]interface{}) string {
+	s, _ := RenderWith(tmpl, vars, MissingKeyKeep)
+	return s
+}
+
+// RenderWith is like Render, with policy for missing keys
+func RenderWith(tmpl string, vars map[string]interface{}, policy MissingKeyPolicy) (string, error) {
+	b := &strings.Builder{}
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		if (c == '{' || c == '}') && i+1 < len(tmpl) && tmpl[i+1] == c {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(tmpl[i+1:], '}')
+		if end < 0 {
+			// unterminated placeholder is literal
+			b.WriteString(tmpl[i:])
+			break
+		}
+
+		name := tmpl[i+1 : i+1+end]
+		if v, ok := lookupVar(vars, strings.TrimSpace(name)); ok {
+			if v != nil {
+				fmt.Fprint(b, v)
+			}
+		} else {
+			switch policy {
+			case MissingKeyError:
+				return "", fmt.Errorf("missing key: %s", name)
+			case MissingKeyKeep:
+				b.WriteString(tmpl[i : i+end+2])
+			}
+		}
+		i += end + 1
+	}
+	return b.String(), nil
+}
+
+func lookupVar(vars map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := vars[name]; ok || len(name) == 0 {
+		return v, ok
+	}
+
+	i := strings.IndexByte(name, '.')
+	if i < 0 {
+		return nil, false
+	}
+
+	switch m := vars[name[:i]].(type) {
+	case map[string]interface{}:
+		return lookupVar(m, name[i+1:])
+	case M:
+		return lookupVar(m, name[i+1:])
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,29 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	vars := gox.M{
+		"name":  "Tom",
+		"count": 3,
+		"user":  gox.M{"email": "tom@example.com"},
+		"nil":   nil,
+	}
+	assert.Equal(t, "Hi Tom, you have 3 messages", gox.Render("Hi {name}, you have {count} messages", vars))
+	assert.Equal(t, "Sent to tom@example.com", gox.Render("Sent to { user.email }", vars))
+	assert.Equal(t, "{name} is Tom", gox.Render("{{name}} is {name}", vars))
+	assert.Equal(t, "Hi {missing}", gox.Render("Hi {missing}", vars))
+	assert.Equal(t, "[]", gox.Render("[{nil}]", vars))
+	assert.Equal(t, "open { brace", gox.Render("open { brace", vars))
+
+	s, err := gox.RenderWith("Hi {missing}!", vars, gox.MissingKeyEmpty)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi !", s)
+	_, err = gox.RenderWith("Hi {missing}!", vars, gox.MissingKeyError)
+	assert.Error(t, err)
+}
@@ -0,0 +1,291 @@
+package gox
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ReportFormat int
+
+const (
+	CSVReport ReportFormat = iota
+	XLSXReport
+)
+
+// ReportColumn describes a column of report
+type ReportColumn struct {
+	Title string
+
+	// Field is the path of value in an item, resolved in the same way as Rule, e.g. "image.url"
+	Field string
+
+	// Format converts value into cell content. Default format is used if it's nil.
+	Format func(v interface{}) string
+}
+
+// WriteReport writes items into w in format. items can be a slice or *AnyList.
+// IDs are written as decimal text, times in RFC3339 and Money with its currency.
+func WriteReport(w io.Writer, format ReportFormat, columns []*ReportColumn, items interface{}) error {
+	var rw reportWriter
+	switch format {
+	case CSVReport:
+		rw = &csvReportWriter{w: csv.NewWriter(w)}
+	case XLSXReport:
+		xw, err := newXLSXReportWriter(w)
+		if err != nil {
+			return err
+		}
+		rw = xw
+	default:
+		return fmt.Errorf("invalid report format %d", format)
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, c := range columns {
+		header[i] = c.Title
+	}
+
+	if err := rw.WriteRow(header); err != nil {
+		return err
+	}
+
+	if l, ok := items.(*AnyList); ok {
+		values := make([]interface{}, l.Size())
+		for i := range values {
+			if a := l.Get(i); a != nil {
+				values[i] = a.Val()
+			}
+		}
+		items = values
+	}
+
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("items is %v instead of slice", v.Kind())
+	}
+
+	row := make([]interface{}, len(columns))
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		for j, c := range columns {
+			f := item
+			for _, name := range strings.Split(c.Field, ".") {
+				f = lookupRuleField(f, name)
+			}
+
+			var val interface{}
+			if f.IsValid() && f.CanInterface() {
+				val = f.Interface()
+			}
+
+			if c.Format != nil {
+				row[j] = c.Format(val)
+			} else {
+				row[j] = reportCellValue(val)
+			}
+		}
+
+		if err := rw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
+
+// FilterAnyList returns values in l whose type name is typ
+func FilterAnyList(l *AnyList, typ string) []interface{} {
+	var values []interface{}
+	for i := 0; i < l.Size(); i++ {
+		if a := l.Get(i); a != nil && a.TypeName() == typ {
+			values = append(values, a.Val())
+		}
+	}
+	return values
+}
+
+// reportCellValue returns string, float64 or nil
+func reportCellValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case ID:
+		return strconv.FormatInt(int64(val), 10)
+	case time.Time:
+		if val.IsZero() {
+			return nil
+		}
+		return val.Format(time.RFC3339)
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return reportCellValue(*val)
+	case Money:
+		return val.String()
+	case *Money:
+		if val == nil {
+			return nil
+		}
+		return val.String()
+	case fmt.Stringer:
+		if IsNil(val) {
+			return nil
+		}
+		return val.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	default:
+		return JSONMarshalStr(rv.Interface())
+	}
+}
+
+type reportWriter interface {
+	WriteRow(row []interface{}) error
+	Close() error
+}
+
+type csvReportWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (w *csvReportWriter) WriteRow(row []interface{}) error {
+	w.record = w.record[:0]
+	for _, v := range row {
+		switch val := v.(type) {
+		case nil:
+			w.record = append(w.record, "")
+		case float64:
+			w.record = append(w.record, strconv.FormatFloat(val, 'f', -1, 64))
+		default:
+			w.record = append(w.record, fmt.Sprint(val))
+		}
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvReportWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	xlsxSheetHeader = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// xlsxReportWriter streams rows into the only sheet of a minimal xlsx package
+type xlsxReportWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+func newXLSXReportWriter(w io.Writer) (*xlsxReportWriter, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(f, p.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(f)
+	if _, err = sheet.WriteString(xlsxSheetHeader); err != nil {
+		return nil, err
+	}
+	return &xlsxReportWriter{zw: zw, sheet: sheet}, nil
+}
+
+func (w *xlsxReportWriter) WriteRow(row []interface{}) error {
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for _, v := range row {
+		switch val := v.(type) {
+		case nil:
+			w.sheet.WriteString(`<c/>`)
+		case float64:
+			fmt.Fprintf(w.sheet, `<c><v>%s</v></c>`, strconv.FormatFloat(val, 'f', -1, 64))
+		default:
+			w.sheet.WriteString(`<c t="inlineStr"><is><t>`)
+			if err := xml.EscapeText(w.sheet, []byte(fmt.Sprint(val))); err != nil {
+				return err
+			}
+			w.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxReportWriter) Close() error {
+	if _, err := w.sheet.WriteString(xlsxSheetFooter); err != nil {
+		return err
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
@@ -0,0 +1,73 @@
+package gox_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reportItem struct {
+	ID        gox.ID     `json:"id"`
+	Name      string     `json:"name"`
+	Price     *gox.Money `json:"price"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func TestWriteReport(t *testing.T) {
+	createdAt := time.Date(2019, 3, 1, 8, 0, 0, 0, time.UTC)
+	items := []*reportItem{
+		{ID: 1234567890123, Name: "a,b", Price: &gox.Money{Currency: gox.USD, Amount: 100}, CreatedAt: createdAt},
+		{ID: 2, Name: "<c>"},
+	}
+	columns := []*gox.ReportColumn{
+		{Title: "ID", Field: "id"},
+		{Title: "Name", Field: "name"},
+		{Title: "Price", Field: "price"},
+		{Title: "Created", Field: "created_at"},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gox.WriteReport(&buf, gox.CSVReport, columns, items))
+		expected := "ID,Name,Price,Created\n" +
+			"1234567890123,\"a,b\",USD 100,2019-03-01T08:00:00Z\n" +
+			"2,<c>,,\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gox.WriteReport(&buf, gox.XLSXReport, columns, items))
+		r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		var sheet string
+		for _, f := range r.File {
+			if f.Name == "xl/worksheets/sheet1.xml" {
+				rc, err := f.Open()
+				require.NoError(t, err)
+				b, err := ioutil.ReadAll(rc)
+				rc.Close()
+				require.NoError(t, err)
+				sheet = string(b)
+			}
+		}
+		assert.Equal(t, 3, strings.Count(sheet, "<row "))
+		assert.Contains(t, sheet, "<t>1234567890123</t>")
+		assert.Contains(t, sheet, "<t>&lt;c&gt;</t>")
+	})
+
+	t.Run("AnyList", func(t *testing.T) {
+		l := gox.NewAnyList(gox.NewAny(&gox.Image{URL: "a.png", Width: 10}), gox.NewAny("text"))
+		var buf bytes.Buffer
+		cols := []*gox.ReportColumn{{Title: "URL", Field: "url"}, {Title: "Width", Field: "width"}}
+		require.NoError(t, gox.WriteReport(&buf, gox.CSVReport, cols, gox.FilterAnyList(l, "image")))
+		assert.Equal(t, "URL,Width\na.png,10\n", buf.String())
+	})
+}
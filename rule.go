@@ -0,0 +1,478 @@
+package gox
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rule is a compiled boolean expression evaluated against Any values, e.g.
+//
+//	type == 'image' && width > 1920
+//
+// Supported operators are ||, &&, !, ==, !=, <, <=, >, >= and parentheses.
+// Operands are numbers, quoted strings, true, false, null and field paths such as video.image.url.
+// A field is matched by its json name, its snake case name or its name ignoring case.
+// "type" refers to the type name of Any, and "value" refers to a non-struct value.
+type Rule struct {
+	src  string
+	root ruleNode
+}
+
+func CompileRule(s string) (*Rule, error) {
+	p := &ruleParser{src: s}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s at %d", p.tokens[p.pos].text, p.tokens[p.pos].offset)
+	}
+	return &Rule{src: s, root: root}, nil
+}
+
+func MustCompileRule(s string) *Rule {
+	r, err := CompileRule(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *Rule) String() string {
+	return r.src
+}
+
+// Match reports whether a satisfies the rule
+func (r *Rule) Match(a *Any) bool {
+	if a == nil {
+		return false
+	}
+	return truthy(r.root.eval(&ruleEnv{typ: a.TypeName(), val: a.Val()}))
+}
+
+// MatchValue reports whether v satisfies the rule, type is resolved by GetAnyTypeName
+func (r *Rule) MatchValue(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	return truthy(r.root.eval(&ruleEnv{typ: GetAnyTypeName(v), val: v}))
+}
+
+type ruleEnv struct {
+	typ string
+	val interface{}
+}
+
+func (e *ruleEnv) lookup(path []string) interface{} {
+	if len(path) == 1 {
+		switch path[0] {
+		case "type":
+			return e.typ
+		case "value":
+			if v := reflect.Indirect(reflect.ValueOf(e.val)); v.IsValid() && v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+				return normalizeRuleValue(v)
+			}
+		}
+	}
+
+	v := reflect.ValueOf(e.val)
+	for _, name := range path {
+		v = lookupRuleField(v, name)
+		if !v.IsValid() {
+			return nil
+		}
+	}
+	return normalizeRuleValue(v)
+}
+
+func lookupRuleField(v reflect.Value, name string) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}
+		}
+		return v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+			if jsonName == name || CamelToSnake(f.Name) == name || strings.EqualFold(f.Name, name) {
+				return v.Field(i)
+			}
+		}
+	}
+	return reflect.Value{}
+}
+
+func normalizeRuleValue(v reflect.Value) interface{} {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	if n, ok := v.Interface().(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return nil
+		}
+		return f
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	default:
+		return v.Interface()
+	}
+}
+
+func truthy(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case nil:
+		return false
+	case float64:
+		return b != 0
+	case string:
+		return len(b) > 0
+	default:
+		return true
+	}
+}
+
+type ruleNode interface {
+	eval(env *ruleEnv) interface{}
+}
+
+type ruleLiteral struct {
+	val interface{}
+}
+
+func (n *ruleLiteral) eval(env *ruleEnv) interface{} {
+	return n.val
+}
+
+type ruleFieldNode struct {
+	path []string
+}
+
+func (n *ruleFieldNode) eval(env *ruleEnv) interface{} {
+	return env.lookup(n.path)
+}
+
+type ruleNot struct {
+	operand ruleNode
+}
+
+func (n *ruleNot) eval(env *ruleEnv) interface{} {
+	return !truthy(n.operand.eval(env))
+}
+
+type ruleBinary struct {
+	op          string
+	left, right ruleNode
+}
+
+func (n *ruleBinary) eval(env *ruleEnv) interface{} {
+	switch n.op {
+	case "&&":
+		return truthy(n.left.eval(env)) && truthy(n.right.eval(env))
+	case "||":
+		return truthy(n.left.eval(env)) || truthy(n.right.eval(env))
+	}
+
+	l, r := n.left.eval(env), n.right.eval(env)
+	switch n.op {
+	case "==":
+		return ruleEqual(l, r)
+	case "!=":
+		return !ruleEqual(l, r)
+	}
+
+	// ordering is only defined between numbers or between strings
+	var c int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case lv < rv:
+			c = -1
+		case lv > rv:
+			c = 1
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false
+		}
+		c = strings.Compare(lv, rv)
+	default:
+		return false
+	}
+
+	switch n.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+type ruleTokenKind int
+
+const (
+	ruleTokenOp ruleTokenKind = iota
+	ruleTokenNumber
+	ruleTokenString
+	ruleTokenIdent
+)
+
+type ruleToken struct {
+	kind   ruleTokenKind
+	text   string
+	offset int
+}
+
+type ruleParser struct {
+	src    string
+	tokens []*ruleToken
+	pos    int
+}
+
+func (p *ruleParser) tokenize() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			p.tokens = append(p.tokens, &ruleToken{ruleTokenOp, s[i : i+1], i})
+			i++
+		case strings.ContainsRune("=!<>&|", rune(c)):
+			if i+1 < len(s) {
+				switch op := s[i : i+2]; op {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					p.tokens = append(p.tokens, &ruleToken{ruleTokenOp, op, i})
+					i += 2
+					continue
+				}
+			}
+
+			if c != '!' && c != '<' && c != '>' {
+				return fmt.Errorf("invalid operator at %d", i)
+			}
+			p.tokens = append(p.tokens, &ruleToken{ruleTokenOp, s[i : i+1], i})
+			i++
+		case c == '\'' || c == '"':
+			b := &strings.Builder{}
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+
+			if j >= len(s) {
+				return fmt.Errorf("unterminated string at %d", i)
+			}
+			p.tokens = append(p.tokens, &ruleToken{ruleTokenString, b.String(), i})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' || c == '.':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' || s[j] == 'e' || s[j] == 'E') {
+				j++
+			}
+			p.tokens = append(p.tokens, &ruleToken{ruleTokenNumber, s[i:j], i})
+			i = j
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] == '.' || s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			p.tokens = append(p.tokens, &ruleToken{ruleTokenIdent, s[i:j], i})
+			i = j
+		default:
+			return fmt.Errorf("unexpected character %q at %d", c, i)
+		}
+	}
+	return nil
+}
+
+func (p *ruleParser) peekOp(ops ...string) string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != ruleTokenOp {
+		return ""
+	}
+
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekOp("||") != "" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &ruleBinary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekOp("&&") != "" {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &ruleBinary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseNot() (ruleNode, error) {
+	if p.peekOp("!") != "" {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleNot{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *ruleParser) parseComparison() (ruleNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if op := p.peekOp("==", "!=", "<", "<=", ">", ">="); op != "" {
+		p.pos++
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &ruleBinary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parsePrimary() (ruleNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of rule")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case ruleTokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at %d", t.text, t.offset)
+		}
+		return &ruleLiteral{val: f}, nil
+	case ruleTokenString:
+		return &ruleLiteral{val: t.text}, nil
+	case ruleTokenIdent:
+		switch t.text {
+		case "true":
+			return &ruleLiteral{val: true}, nil
+		case "false":
+			return &ruleLiteral{val: false}, nil
+		case "null", "nil":
+			return &ruleLiteral{val: nil}, nil
+		}
+		return &ruleFieldNode{path: strings.Split(t.text, ".")}, nil
+	default:
+		if t.text == "(" {
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if p.peekOp(")") == "" {
+				return nil, fmt.Errorf("missing ) for ( at %d", t.offset)
+			}
+			p.pos++
+			return n, nil
+		}
+		return nil, fmt.Errorf("unexpected %s at %d", t.text, t.offset)
+	}
+}
+
+// ruleEqual compares l and r deeply if any of them is uncomparable, e.g. slices and maps, which panics with ==
+func ruleEqual(l, r interface{}) bool {
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+
+	if !reflect.TypeOf(l).Comparable() || !reflect.TypeOf(r).Comparable() {
+		return reflect.DeepEqual(l, r)
+	}
+	return l == r
+}
@@ -0,0 +1,59 @@
+package gox_test
+
+import (
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Match(t *testing.T) {
+	image := gox.NewAny(&gox.Image{URL: "https://a.com/1.png", Width: 2048, Height: 1024, Format: "png"})
+	video := gox.NewAny(&gox.Video{URL: "https://a.com/1.mp4", Image: &gox.Image{Width: 640}})
+	text := gox.NewAny("hello")
+
+	tests := []struct {
+		Rule    string
+		Matches []bool
+	}{
+		{"type == 'image' && width > 1920", []bool{true, false, false}},
+		{"type == 'image' && (w <= 1920 || fmt == \"png\")", []bool{true, false, false}},
+		{"!(type == 'image')", []bool{false, true, true}},
+		{"image.width >= 640", []bool{false, true, false}},
+		{"value == 'hello' || url != null", []bool{true, true, true}},
+		{"size > 0", []bool{false, false, false}},
+	}
+
+	for _, tc := range tests {
+		r, err := gox.CompileRule(tc.Rule)
+		require.NoError(t, err, tc.Rule)
+		assert.Equal(t, tc.Matches, []bool{r.Match(image), r.Match(video), r.Match(text)}, tc.Rule)
+	}
+}
+
+func TestCompileRule_Error(t *testing.T) {
+	for _, s := range []string{"", "a ==", "(a == 1", "a = 1", "'abc", "a == 1 b"} {
+		_, err := gox.CompileRule(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRule_MatchValue_Uncomparable(t *testing.T) {
+	v := map[string]interface{}{"tags": []string{"a"}, "attrs": map[string]int{"a": 1}, "name": "x"}
+	tests := map[string]bool{
+		"tags == tags":  true,
+		"tags != tags":  false,
+		"tags == attrs": false,
+		"tags == 'a'":   false,
+		"attrs != name": true,
+		"attrs == null": false,
+	}
+	for s, expected := range tests {
+		r, err := gox.CompileRule(s)
+		require.NoError(t, err, s)
+		assert.NotPanics(t, func() {
+			assert.Equal(t, expected, r.MatchValue(v), s)
+		}, s)
+	}
+}
@@ -0,0 +1,133 @@
+package gox
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Sampler decides whether an event should be recorded, e.g. a log line or a telemetry point
+type Sampler interface {
+	Sample() bool
+}
+
+type SamplerFunc func() bool
+
+func (f SamplerFunc) Sample() bool {
+	return f()
+}
+
+var AlwaysSample SamplerFunc = func() bool {
+	return true
+}
+
+var NeverSample SamplerFunc = func() bool {
+	return false
+}
+
+type probabilitySampler struct {
+	rate float64
+}
+
+// NewProbabilitySampler returns a sampler which keeps events with probability rate in [0,1]
+func NewProbabilitySampler(rate float64) Sampler {
+	if rate <= 0 {
+		return NeverSample
+	}
+
+	if rate >= 1 {
+		return AlwaysSample
+	}
+	return &probabilitySampler{rate: rate}
+}
+
+func (s *probabilitySampler) Sample() bool {
+	return rand.Float64() < s.rate
+}
+
+type rateLimitSampler struct {
+	mu       sync.Mutex
+	limit    int
+	interval time.Duration
+	start    time.Time
+	count    int
+}
+
+// NewRateLimitSampler returns a sampler which keeps at most limit events in every interval
+func NewRateLimitSampler(limit int, interval time.Duration) Sampler {
+	if limit <= 0 {
+		return NeverSample
+	}
+
+	if interval <= 0 {
+		panic("interval should be positive")
+	}
+	return &rateLimitSampler{limit: limit, interval: interval}
+}
+
+func (s *rateLimitSampler) Sample() bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.start) >= s.interval {
+		s.start = now
+		s.count = 0
+	}
+
+	if s.count >= s.limit {
+		return false
+	}
+	s.count++
+	return true
+}
+
+// IDSampler samples by key consistently: the same id or key always gets the same decision,
+// so all events of one entity are either kept or dropped together across services
+type IDSampler struct {
+	threshold uint64
+}
+
+func NewIDSampler(rate float64) *IDSampler {
+	s := &IDSampler{}
+	switch {
+	case rate <= 0:
+		s.threshold = 0
+	case rate >= 1:
+		s.threshold = math.MaxUint64
+	default:
+		s.threshold = uint64(rate * math.MaxUint64)
+	}
+	return s
+}
+
+func (s *IDSampler) SampleID(id ID) bool {
+	return s.sample(mix64(uint64(id)))
+}
+
+func (s *IDSampler) SampleKey(key string) bool {
+	// FNV-1a
+	var h uint64 = 14695981039346656037
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	return s.sample(mix64(h))
+}
+
+func (s *IDSampler) sample(h uint64) bool {
+	if s.threshold == math.MaxUint64 {
+		return true
+	}
+	return h < s.threshold
+}
+
+// mix64 is the finalizer of splitmix64 which spreads sequential ids uniformly
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,35 @@
+package gox_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDSampler(t *testing.T) {
+	s := gox.NewIDSampler(0.25)
+	n := 0
+	for i := 0; i < 10000; i++ {
+		id := gox.ID(i)
+		if s.SampleID(id) {
+			n++
+		}
+		assert.Equal(t, s.SampleID(id), s.SampleID(id))
+	}
+	assert.InDelta(t, 2500, n, 300)
+	assert.True(t, gox.NewIDSampler(1).SampleKey("a"))
+	assert.False(t, gox.NewIDSampler(0).SampleKey("a"))
+}
+
+func TestRateLimitSampler(t *testing.T) {
+	s := gox.NewRateLimitSampler(3, time.Hour)
+	n := 0
+	for i := 0; i < 10; i++ {
+		if s.Sample() {
+			n++
+		}
+	}
+	assert.Equal(t, 3, n)
+}
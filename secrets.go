@@ -0,0 +1,154 @@
+package gox
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ErrSecretNotFound ErrorString = "secret not found"
+
+// Secrets provides secret values such as keys and passwords by name
+type Secrets interface {
+	Get(ctx context.Context, name string) ([]byte, error)
+}
+
+// SecretsFunc adapts a function to Secrets, e.g. a lookup in Vault or a KMS decryption
+type SecretsFunc func(ctx context.Context, name string) ([]byte, error)
+
+func (f SecretsFunc) Get(ctx context.Context, name string) ([]byte, error) {
+	return f(ctx, name)
+}
+
+type envSecrets struct {
+	prefix string
+}
+
+// EnvSecrets reads secrets from environment variables.
+// Name is converted to upper snake case and prefixed, e.g. name "db.password" with prefix "APP_" is read from APP_DB_PASSWORD
+func EnvSecrets(prefix string) Secrets {
+	return &envSecrets{prefix: prefix}
+}
+
+func (s *envSecrets) Get(ctx context.Context, name string) ([]byte, error) {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	v, ok := os.LookupEnv(s.prefix + key)
+	if !ok {
+		return nil, ErrSecretNotFound
+	}
+	return []byte(v), nil
+}
+
+type fileSecrets struct {
+	dir string
+}
+
+// FileSecrets reads secrets from files in dir, one file per secret, as mounted by docker or kubernetes.
+// Trailing newline is trimmed.
+func FileSecrets(dir string) Secrets {
+	return &fileSecrets{dir: dir}
+}
+
+func (s *fileSecrets) Get(ctx context.Context, name string) ([]byte, error) {
+	if len(name) == 0 || name != filepath.Base(name) || name == "." || name == ".." {
+		return nil, BadRequest("invalid secret name: " + name)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, err
+	}
+	return bytes.TrimRight(b, "\r\n"), nil
+}
+
+type cachedSecret struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// CachedSecrets caches secrets of source for ttl, and notifies rotation callbacks if a refreshed value differs
+type CachedSecrets struct {
+	source Secrets
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	cache     map[string]*cachedSecret
+	callbacks []func(name string, value []byte)
+}
+
+var _ Secrets = (*CachedSecrets)(nil)
+
+func NewCachedSecrets(source Secrets, ttl time.Duration) *CachedSecrets {
+	if source == nil {
+		panic("source is nil")
+	}
+	return &CachedSecrets{
+		source: source,
+		ttl:    ttl,
+		cache:  make(map[string]*cachedSecret),
+	}
+}
+
+func (s *CachedSecrets) Get(ctx context.Context, name string) ([]byte, error) {
+	s.mu.Lock()
+	c := s.cache[name]
+	s.mu.Unlock()
+	if c != nil && time.Now().Before(c.expiresAt) {
+		return copyBytes(c.value), nil
+	}
+	return s.Refresh(ctx, name)
+}
+
+// Refresh reloads secret name from source. Like Get, it returns a copy which the caller may modify or zero.
+func (s *CachedSecrets) Refresh(ctx context.Context, name string) ([]byte, error) {
+	v, err := s.source.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	v = copyBytes(v)
+	s.mu.Lock()
+	old := s.cache[name]
+	s.cache[name] = &cachedSecret{value: v, expiresAt: time.Now().Add(s.ttl)}
+	var callbacks []func(string, []byte)
+	if old != nil && !bytes.Equal(old.value, v) {
+		callbacks = append(callbacks, s.callbacks...)
+	}
+	s.mu.Unlock()
+
+	for _, f := range callbacks {
+		f(name, copyBytes(v))
+	}
+	return copyBytes(v), nil
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
+
+// OnRotate registers f which is called after a secret's value is changed
+func (s *CachedSecrets) OnRotate(f func(name string, value []byte)) {
+	s.mu.Lock()
+	s.callbacks = append(s.callbacks, f)
+	s.mu.Unlock()
+}
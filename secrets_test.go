@@ -0,0 +1,148 @@
+package gox_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSecrets(t *testing.T) {
+	require.NoError(t, os.Setenv("GOX_TEST_DB_PASSWORD", "p@ss"))
+	defer os.Unsetenv("GOX_TEST_DB_PASSWORD")
+
+	s := gox.EnvSecrets("GOX_TEST_")
+	v, err := s.Get(context.Background(), "db.password")
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", string(v))
+
+	v, err = s.Get(context.Background(), "DB-Password")
+	require.NoError(t, err)
+	assert.Equal(t, "p@ss", string(v))
+
+	_, err = s.Get(context.Background(), "db.user")
+	assert.Equal(t, gox.ErrSecretNotFound, err)
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "api_key"), []byte("abc\n"), 0600))
+
+	// a secret outside dir, which must not be reachable
+	require.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(dir), "outside_secret"), []byte("x"), 0600))
+	defer os.Remove(filepath.Join(filepath.Dir(dir), "outside_secret"))
+
+	s := gox.FileSecrets(dir)
+	v, err := s.Get(context.Background(), "api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(v))
+
+	_, err = s.Get(context.Background(), "missing")
+	assert.Equal(t, gox.ErrSecretNotFound, err)
+
+	for _, name := range []string{"", ".", "..", "../outside_secret", "sub/api_key", "/etc/passwd"} {
+		v, err := s.Get(context.Background(), name)
+		assert.Error(t, err, name)
+		assert.NotEqual(t, gox.ErrSecretNotFound, err, name)
+		assert.Nil(t, v, name)
+	}
+}
+
+func TestCachedSecrets(t *testing.T) {
+	var calls int32
+	var value atomic.Value
+	value.Store("v1")
+	source := gox.SecretsFunc(func(ctx context.Context, name string) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		if name != "key" {
+			return nil, gox.ErrSecretNotFound
+		}
+		return []byte(value.Load().(string)), nil
+	})
+
+	s := gox.NewCachedSecrets(source, 50*time.Millisecond)
+	var rotated []string
+	s.OnRotate(func(name string, v []byte) {
+		rotated = append(rotated, name+"="+string(v))
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		v, err := s.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, "v1", string(v))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// cached value is served until it expires
+	value.Store("v2")
+	v, err := s.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(v))
+	assert.Empty(t, rotated)
+
+	time.Sleep(60 * time.Millisecond)
+	v, err = s.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(v))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, []string{"key=v2"}, rotated)
+
+	// unchanged value isn't a rotation
+	_, err = s.Refresh(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key=v2"}, rotated)
+
+	// errors aren't cached
+	_, err = s.Get(ctx, "other")
+	assert.Equal(t, gox.ErrSecretNotFound, err)
+	_, err = s.Get(ctx, "other")
+	assert.Equal(t, gox.ErrSecretNotFound, err)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+}
+
+func TestCachedSecrets_Copy(t *testing.T) {
+	source := gox.SecretsFunc(func(ctx context.Context, name string) ([]byte, error) {
+		return []byte("secret"), nil
+	})
+
+	s := gox.NewCachedSecrets(source, time.Minute)
+	v, err := s.Get(context.Background(), "key")
+	require.NoError(t, err)
+	for i := range v {
+		v[i] = 0
+	}
+
+	v, err = s.Get(context.Background(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(v))
+}
+
+func TestCachedSecrets_RefreshError(t *testing.T) {
+	fail := int32(0)
+	source := gox.SecretsFunc(func(ctx context.Context, name string) ([]byte, error) {
+		if atomic.LoadInt32(&fail) == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return []byte("v1"), nil
+	})
+
+	s := gox.NewCachedSecrets(source, time.Millisecond)
+	_, err := s.Get(context.Background(), "key")
+	require.NoError(t, err)
+
+	atomic.StoreInt32(&fail, 1)
+	time.Sleep(5 * time.Millisecond)
+	_, err = s.Get(context.Background(), "key")
+	assert.Error(t, err)
+}
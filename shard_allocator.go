@@ -0,0 +1,295 @@
+package gox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopub/log"
+)
+
+const (
+	ErrNoShardAvailable ErrorString = "no shard available"
+	ErrShardLeaseLost   ErrorString = "shard lease lost"
+)
+
+// ShardLeaser grants exclusive leases on keys which expire after ttl unless renewed.
+// It can be implemented with Redis SET NX PX, etcd leases or a database table.
+type ShardLeaser interface {
+	// Acquire leases key to owner if key isn't leased, and reports whether it succeeded
+	Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+
+	// Renew extends lease of key if it's still held by owner, and reports whether it succeeded
+	Renew(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+
+	// Release removes lease of key if it's held by owner
+	Release(ctx context.Context, key, owner string) error
+}
+
+const (
+	redisAcquireScript = `if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then return 1 end return 0`
+	redisRenewScript   = `if redis.call('GET', KEYS[1]) == ARGV[1] then return redis.call('PEXPIRE', KEYS[1], ARGV[2]) end return 0`
+	redisReleaseScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then return redis.call('DEL', KEYS[1]) end return 0`
+)
+
+type redisShardLeaser struct {
+	scripter RedisScripter
+}
+
+func NewRedisShardLeaser(scripter RedisScripter) ShardLeaser {
+	return &redisShardLeaser{scripter: scripter}
+}
+
+func (l *redisShardLeaser) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return l.eval(ctx, redisAcquireScript, key, owner, int64(ttl/time.Millisecond))
+}
+
+func (l *redisShardLeaser) Renew(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return l.eval(ctx, redisRenewScript, key, owner, int64(ttl/time.Millisecond))
+}
+
+func (l *redisShardLeaser) Release(ctx context.Context, key, owner string) error {
+	_, err := l.eval(ctx, redisReleaseScript, key, owner)
+	return err
+}
+
+func (l *redisShardLeaser) eval(ctx context.Context, script, key string, args ...interface{}) (bool, error) {
+	v, err := l.scripter.Eval(ctx, script, []string{key}, args...)
+	if err != nil {
+		return false, err
+	}
+
+	n, ok := v.(int64)
+	if !ok && v != nil {
+		return false, fmt.Errorf("unexpected reply %v", v)
+	}
+	return n == 1, nil
+}
+
+type memoryLease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+type memoryShardLeaser struct {
+	mu     sync.Mutex
+	leases map[string]*memoryLease
+}
+
+// NewMemoryShardLeaser returns a ShardLeaser in memory, which only coordinates allocators in the same process
+func NewMemoryShardLeaser() ShardLeaser {
+	return &memoryShardLeaser{leases: make(map[string]*memoryLease)}
+}
+
+func (l *memoryShardLeaser) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v := l.leases[key]; v != nil && time.Now().Before(v.expiresAt) {
+		return false, nil
+	}
+	l.leases[key] = &memoryLease{owner: owner, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (l *memoryShardLeaser) Renew(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v := l.leases[key]
+	if v == nil || v.owner != owner || !time.Now().Before(v.expiresAt) {
+		return false, nil
+	}
+	v.expiresAt = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (l *memoryShardLeaser) Release(ctx context.Context, key, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v := l.leases[key]; v != nil && v.owner == owner {
+		delete(l.leases, key)
+	}
+	return nil
+}
+
+// ShardAllocator leases a unique shard id for an id generator, and renews the lease in background until it's released.
+type ShardAllocator struct {
+	leaser    ShardLeaser
+	prefix    string
+	numShards int64
+	ttl       time.Duration
+	owner     string
+
+	// shardID is read atomically by getters, and written with mu held
+	shardID int64
+
+	mu     sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+	onLost func(shardID int64)
+}
+
+// NewShardAllocator creates an allocator leasing keys prefix+shardID for shard ids in [0, 1<<shardBitSize)
+func NewShardAllocator(leaser ShardLeaser, prefix string, shardBitSize uint, ttl time.Duration) *ShardAllocator {
+	if leaser == nil {
+		panic("leaser is nil")
+	}
+
+	if shardBitSize == 0 || shardBitSize > 16 {
+		panic("shardBitSize should be in [1, 16]")
+	}
+
+	if ttl < 3*time.Millisecond {
+		panic("ttl is too short")
+	}
+
+	host, _ := os.Hostname()
+	return &ShardAllocator{
+		leaser:    leaser,
+		prefix:    prefix,
+		numShards: 1 << shardBitSize,
+		ttl:       ttl,
+		owner:     host + "-" + strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(randInt63(), 36),
+		shardID:   -1,
+	}
+}
+
+// OnLost sets f which is called if the lease can't be renewed before it expires.
+// Ids generated afterwards may collide with another instance, so it's suggested to stop generating ids.
+func (a *ShardAllocator) OnLost(f func(shardID int64)) {
+	a.mu.Lock()
+	a.onLost = f
+	a.mu.Unlock()
+}
+
+// Acquire leases a free shard id and returns a NumberGetter of it, which also implements NumberGetterE.
+// The getter reads the shard id of the current lease, and fails with ErrShardLeaseLost while no shard is leased,
+// so that a generator using it stops issuing ids once the lease is lost or released, until Acquire succeeds again.
+func (a *ShardAllocator) Acquire(ctx context.Context) (NumberGetter, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ShardID() >= 0 {
+		return leasedShardID{a}, nil
+	}
+
+	// start from a random shard to reduce contention between instances starting together
+	start := randInt63() % a.numShards
+	for i := int64(0); i < a.numShards; i++ {
+		id := (start + i) % a.numShards
+		ok, err := a.leaser.Acquire(ctx, a.key(id), a.owner, a.ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			atomic.StoreInt64(&a.shardID, id)
+			a.stop = make(chan struct{})
+			a.done = make(chan struct{})
+			go a.renew(id, a.stop, a.done)
+			return leasedShardID{a}, nil
+		}
+	}
+	return nil, ErrNoShardAvailable
+}
+
+// ShardID returns the leased shard id, or -1 if no shard is leased
+func (a *ShardAllocator) ShardID() int64 {
+	return atomic.LoadInt64(&a.shardID)
+}
+
+// Release stops renewal and releases the lease
+func (a *ShardAllocator) Release(ctx context.Context) error {
+	a.mu.Lock()
+	id, stop, done := a.ShardID(), a.stop, a.done
+	atomic.StoreInt64(&a.shardID, -1)
+	a.stop, a.done = nil, nil
+	a.mu.Unlock()
+	if id < 0 {
+		return nil
+	}
+
+	close(stop)
+	<-done
+	return a.leaser.Release(ctx, a.key(id), a.owner)
+}
+
+func (a *ShardAllocator) key(shardID int64) string {
+	return a.prefix + strconv.FormatInt(shardID, 10)
+}
+
+func (a *ShardAllocator) renew(shardID int64, stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(a.ttl / 3)
+	defer ticker.Stop()
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), a.ttl/3)
+		ok, err := a.leaser.Renew(ctx, a.key(shardID), a.owner, a.ttl)
+		cancel()
+		if err != nil {
+			log.Error(err)
+			if time.Since(renewedAt) < a.ttl {
+				continue
+			}
+		}
+
+		if ok {
+			renewedAt = time.Now()
+			continue
+		}
+
+		a.mu.Lock()
+		if a.stop == stop {
+			// Acquire picks a shard again
+			atomic.StoreInt64(&a.shardID, -1)
+			a.stop, a.done = nil, nil
+		}
+		f := a.onLost
+		a.mu.Unlock()
+		log.Error("Lost lease of shard", shardID)
+		if f != nil {
+			f(shardID)
+		}
+		return
+	}
+}
+
+// leasedShardID is the shard id getter returned by ShardAllocator.Acquire
+type leasedShardID struct {
+	a *ShardAllocator
+}
+
+var _ NumberGetterE = leasedShardID{}
+
+// GetNumber returns the leased shard id, or -1 if no shard is leased
+func (g leasedShardID) GetNumber() int64 {
+	return g.a.ShardID()
+}
+
+func (g leasedShardID) GetNumberE() (int64, error) {
+	id := g.a.ShardID()
+	if id < 0 {
+		return 0, ErrShardLeaseLost
+	}
+	return id, nil
+}
+
+// randInt63 returns a non-negative random number from crypto/rand, which needs no seeding
+func randInt63() int64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1)
+}
@@ -0,0 +1,86 @@
+package gox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardAllocator(t *testing.T) {
+	ctx := context.Background()
+	leaser := gox.NewMemoryShardLeaser()
+	seen := make(map[int64]bool)
+	var allocators []*gox.ShardAllocator
+	for i := 0; i < 4; i++ {
+		a := gox.NewShardAllocator(leaser, "shard:", 2, 30*time.Millisecond)
+		g, err := a.Acquire(ctx)
+		require.NoError(t, err)
+		id := g.GetNumber()
+		assert.False(t, seen[id], id)
+		seen[id] = true
+		allocators = append(allocators, a)
+	}
+
+	extra := gox.NewShardAllocator(leaser, "shard:", 2, 30*time.Millisecond)
+	_, err := extra.Acquire(ctx)
+	assert.Equal(t, gox.ErrNoShardAvailable, err)
+
+	// leases are renewed beyond ttl
+	time.Sleep(60 * time.Millisecond)
+	_, err = extra.Acquire(ctx)
+	assert.Equal(t, gox.ErrNoShardAvailable, err)
+
+	id := allocators[0].ShardID()
+	require.NoError(t, allocators[0].Release(ctx))
+	assert.Equal(t, int64(-1), allocators[0].ShardID())
+	g, err := extra.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, id, g.GetNumber())
+
+	for _, a := range append(allocators[1:], extra) {
+		require.NoError(t, a.Release(ctx))
+	}
+}
+
+type failingRenewLeaser struct {
+	gox.ShardLeaser
+}
+
+func (l failingRenewLeaser) Renew(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return false, nil
+}
+
+func TestShardAllocator_Lost(t *testing.T) {
+	ctx := context.Background()
+	a := gox.NewShardAllocator(failingRenewLeaser{gox.NewMemoryShardLeaser()}, "shard:", 2, 30*time.Millisecond)
+	lost := make(chan int64, 1)
+	a.OnLost(func(shardID int64) {
+		lost <- shardID
+	})
+
+	getter, err := a.Acquire(ctx)
+	require.NoError(t, err)
+	g := gox.NewSnakeIDGenerator(2, 8, nil, getter, nil)
+	_, err = g.TryNextID()
+	require.NoError(t, err)
+
+	id := <-lost
+	assert.Equal(t, int64(-1), a.ShardID())
+	assert.Equal(t, int64(-1), getter.GetNumber())
+	_, err = getter.(gox.NumberGetterE).GetNumberE()
+	assert.Equal(t, gox.ErrShardLeaseLost, err)
+	_, err = g.TryNextID()
+	assert.Equal(t, gox.ErrShardLeaseLost, err)
+
+	// the previous lease has expired, so any shard may be picked
+	_, err = a.Acquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, a.ShardID() >= 0, id)
+	_, err = g.TryNextID()
+	assert.NoError(t, err)
+	require.NoError(t, a.Release(ctx))
+}
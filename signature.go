@@ -0,0 +1,238 @@
+package gox
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+const ErrUnsupportedKey ErrorString = "unsupported key"
+
+// oidEd25519 is defined in RFC 8410. crypto/x509 doesn't support ed25519 keys before go1.13, so they are encoded here.
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+type pkcs8Key struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+type publicKeyInfo struct {
+	Algo      pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// GenerateKeypair generates an Ed25519 key pair
+func GenerateKeypair() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(RandSource())
+}
+
+// GenerateRSAKeypair generates a RSA key pair for RSA-PSS signatures. bits is recommended 2048 or more.
+func GenerateRSAKeypair(bits int) (*rsa.PublicKey, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(RandSource(), bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &key.PublicKey, key, nil
+}
+
+// Sign signs msg with an ed25519.PrivateKey, or a *rsa.PrivateKey in RSA-PSS with SHA-256
+func Sign(key crypto.Signer, msg []byte) ([]byte, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return ed25519.Sign(k, msg), nil
+	case *rsa.PrivateKey:
+		sum := sha256.Sum256(msg)
+		return rsa.SignPSS(RandSource(), k, crypto.SHA256, sum[:], nil)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// Verify reports whether sig is the signature of msg returned by Sign with the private key of pub
+func Verify(pub crypto.PublicKey, msg, sig []byte) bool {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return len(k) == ed25519.PublicKeySize && ed25519.Verify(k, msg, sig)
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(msg)
+		return rsa.VerifyPSS(k, crypto.SHA256, sum[:], sig, nil) == nil
+	default:
+		return false
+	}
+}
+
+// MarshalPrivateKeyPEM encodes an ed25519.PrivateKey or a *rsa.PrivateKey in PKCS #8 PEM
+func MarshalPrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	var der []byte
+	var err error
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		seed, _ := asn1.Marshal(k[:ed25519.SeedSize])
+		der, err = asn1.Marshal(pkcs8Key{
+			Algo:       pkix.AlgorithmIdentifier{Algorithm: oidEd25519},
+			PrivateKey: seed,
+		})
+	case *rsa.PrivateKey:
+		der, err = x509.MarshalPKCS8PrivateKey(k)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM parses an Ed25519 or RSA private key in PKCS #8 PEM, or a RSA private key in PKCS #1 PEM
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem data")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		var k pkcs8Key
+		if _, err := asn1.Unmarshal(block.Bytes, &k); err != nil {
+			return nil, err
+		}
+
+		if k.Algo.Algorithm.Equal(oidEd25519) {
+			var seed []byte
+			if _, err := asn1.Unmarshal(k.PrivateKey, &seed); err != nil {
+				return nil, err
+			}
+
+			if len(seed) != ed25519.SeedSize {
+				return nil, fmt.Errorf("invalid ed25519 seed length: %d", len(seed))
+			}
+			return ed25519.NewKeyFromSeed(seed), nil
+		}
+
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		if s, ok := key.(*rsa.PrivateKey); ok {
+			return s, nil
+		}
+		return nil, ErrUnsupportedKey
+	default:
+		return nil, fmt.Errorf("unsupported pem type: %s", block.Type)
+	}
+}
+
+// MarshalPublicKeyPEM encodes an ed25519.PublicKey or a *rsa.PublicKey in PKIX PEM
+func MarshalPublicKeyPEM(pub crypto.PublicKey) ([]byte, error) {
+	var der []byte
+	var err error
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		der, err = asn1.Marshal(publicKeyInfo{
+			Algo:      pkix.AlgorithmIdentifier{Algorithm: oidEd25519},
+			PublicKey: asn1.BitString{Bytes: k, BitLength: 8 * len(k)},
+		})
+	case *rsa.PublicKey:
+		der, err = x509.MarshalPKIXPublicKey(k)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePublicKeyPEM parses an Ed25519 or RSA public key in PKIX PEM
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem data")
+	}
+
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unsupported pem type: %s", block.Type)
+	}
+
+	var info publicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, err
+	}
+
+	if info.Algo.Algorithm.Equal(oidEd25519) {
+		if len(info.PublicKey.Bytes) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key length: %d", len(info.PublicKey.Bytes))
+		}
+		return ed25519.PublicKey(info.PublicKey.Bytes), nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if p, ok := key.(*rsa.PublicKey); ok {
+		return p, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// SavePrivateKey writes key in PEM into file, which is readable only by the owner
+func SavePrivateKey(filename string, key crypto.Signer) error {
+	b, err := MarshalPrivateKeyPEM(key)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0600)
+}
+
+// LoadPrivateKey reads a private key in PEM from file
+func LoadPrivateKey(filename string) (crypto.Signer, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKeyPEM(b)
+}
+
+// SavePublicKey writes pub in PEM into file
+func SavePublicKey(filename string, pub crypto.PublicKey) error {
+	b, err := MarshalPublicKeyPEM(pub)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0644)
+}
+
+// LoadPublicKey reads a public key in PEM from file
+func LoadPublicKey(filename string) (crypto.PublicKey, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeyPEM(b)
+}
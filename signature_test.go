@@ -0,0 +1,63 @@
+package gox_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	pub, priv, err := gox.GenerateKeypair()
+	require.NoError(t, err)
+	sig, err := gox.Sign(priv, []byte(quickFox))
+	require.NoError(t, err)
+	assert.True(t, gox.Verify(pub, []byte(quickFox), sig))
+	assert.False(t, gox.Verify(pub, []byte(quickFox+"."), sig))
+
+	rsaPub, rsaPriv, err := gox.GenerateRSAKeypair(2048)
+	require.NoError(t, err)
+	sig, err = gox.Sign(rsaPriv, []byte(quickFox))
+	require.NoError(t, err)
+	assert.True(t, gox.Verify(rsaPub, []byte(quickFox), sig))
+	assert.False(t, gox.Verify(pub, []byte(quickFox), sig))
+}
+
+func TestKeyPEM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pub, priv, err := gox.GenerateKeypair()
+	require.NoError(t, err)
+	require.NoError(t, gox.SavePrivateKey(filepath.Join(dir, "ed25519.key"), priv))
+	require.NoError(t, gox.SavePublicKey(filepath.Join(dir, "ed25519.pub"), pub))
+	priv2, err := gox.LoadPrivateKey(filepath.Join(dir, "ed25519.key"))
+	require.NoError(t, err)
+	assert.Equal(t, priv, priv2)
+	pub2, err := gox.LoadPublicKey(filepath.Join(dir, "ed25519.pub"))
+	require.NoError(t, err)
+	assert.Equal(t, pub, pub2)
+
+	rsaPub, rsaPriv, err := gox.GenerateRSAKeypair(1024)
+	require.NoError(t, err)
+	b, err := gox.MarshalPrivateKeyPEM(rsaPriv)
+	require.NoError(t, err)
+	k, err := gox.ParsePrivateKeyPEM(b)
+	require.NoError(t, err)
+	sig, err := gox.Sign(k, []byte(quickFox))
+	require.NoError(t, err)
+	assert.True(t, gox.Verify(rsaPub, []byte(quickFox), sig))
+	b, err = gox.MarshalPublicKeyPEM(rsaPub)
+	require.NoError(t, err)
+	p, err := gox.ParsePublicKeyPEM(b)
+	require.NoError(t, err)
+	assert.Equal(t, rsaPub, p)
+
+	_, err = gox.ParsePublicKeyPEM([]byte("garbage"))
+	assert.Error(t, err)
+}
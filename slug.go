@@ -0,0 +1,74 @@
+package gox
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSlugMaxLen is the max length of Slugify in runes
+const DefaultSlugMaxLen = 80
+
+// transliterations maps latin letters with diacritics and ligatures to ascii
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ĝ': "g", 'ģ': "g", 'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j", 'ķ': "k", 'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ř': "r", 'ś': "s", 'ş': "s", 'š': "s", 'ŝ': "s", 'ș': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slugify converts s into a lower case slug for urls and file names, e.g. "Crème Brûlée: A Recipe!" to "creme-brulee-a-recipe".
+// Latin letters with diacritics are transliterated to ascii, other letters such as CJK are kept,
+// and runs of other characters are collapsed to a hyphen. The result is cut at a hyphen within DefaultSlugMaxLen runes if possible.
+func Slugify(s string) string {
+	return SlugifyN(s, DefaultSlugMaxLen)
+}
+
+// SlugifyN is Slugify with max length n in runes
+func SlugifyN(s string, n int) string {
+	var slug []rune
+	hyphen := false
+	for _, c := range strings.ToLower(s) {
+		if t, ok := transliterations[c]; ok {
+			if hyphen && len(slug) > 0 {
+				slug = append(slug, '-')
+			}
+			hyphen = false
+			slug = append(slug, []rune(t)...)
+			continue
+		}
+
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if hyphen && len(slug) > 0 {
+				slug = append(slug, '-')
+			}
+			hyphen = false
+			slug = append(slug, c)
+			continue
+		}
+
+		// apostrophes are dropped without breaking words, e.g. don't to dont
+		if c != '\'' && c != '’' && !unicode.Is(unicode.Mn, c) {
+			hyphen = true
+		}
+	}
+
+	if n > 0 && len(slug) > n {
+		cut := n
+		for i := n; i > n/2; i-- {
+			if slug[i] == '-' {
+				cut = i
+				break
+			}
+		}
+		slug = slug[:cut]
+	}
+	return strings.Trim(string(slug), "-")
+}
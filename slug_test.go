@@ -0,0 +1,30 @@
+package gox_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		Input  string
+		Output string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Crème Brûlée: A Recipe!  ", "creme-brulee-a-recipe"},
+		{"Don't Stop--Me_Now", "dont-stop-me-now"},
+		{"Straße in Łódź", "strasse-in-lodz"},
+		{"Go 1.12 发布", "go-1-12-发布"},
+		{"!!!", ""},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.Output, gox.Slugify(tc.Input), tc.Input)
+	}
+
+	assert.Equal(t, "the-quick-brown", gox.SlugifyN(quickFox, 18))
+	assert.Equal(t, "abcdefghij", gox.SlugifyN(strings.Repeat("abcdefghij", 3), 10))
+	assert.True(t, len(gox.Slugify(strings.Repeat("word ", 100))) <= gox.DefaultSlugMaxLen)
+}
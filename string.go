@@ -3,6 +3,8 @@ package gox
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 func SmartLen(s string) int {
@@ -26,3 +28,301 @@ func CombineSpaces(s string) string {
 	s = whitespaceRegexp.ReplaceAllString(s, " ")
 	return s
 }
+
+// Truncate cuts s to at most max runes including suffix, e.g. Truncate(s, 100, "..."). s is returned as is if it fits.
+// It never splits a multi-byte character, but may split a grapheme cluster such as an emoji sequence. See TruncateGraphemes.
+func Truncate(s string, max int, suffix string) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	n := max - utf8.RuneCountInString(suffix)
+	if n <= 0 {
+		return truncateRunes(suffix, max)
+	}
+	return truncateRunes(s, n) + suffix
+}
+
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	i := 0
+	for k := range s {
+		if i == n {
+			return s[:k]
+		}
+		i++
+	}
+	return s
+}
+
+// TruncateGraphemes is like Truncate, but counts grapheme clusters, which keeps accents, emoji with skin tones,
+// flags and ZWJ sequences such as 👨‍👩‍👧 intact. Clusters are approximated without the full rules of UAX #29.
+func TruncateGraphemes(s string, max int, suffix string) string {
+	if max <= 0 {
+		return ""
+	}
+
+	clusters := graphemeBounds(s)
+	if len(clusters) <= max {
+		return s
+	}
+
+	n := max - len(graphemeBounds(suffix))
+	if n <= 0 {
+		return TruncateGraphemes(suffix, max, "")
+	}
+	return s[:clusters[n]] + suffix
+}
+
+// graphemeBounds returns start offsets of grapheme clusters in s
+func graphemeBounds(s string) []int {
+	var bounds []int
+	var prev rune
+	regional := 0
+	for i, c := range s {
+		extend := false
+		switch {
+		case i == 0:
+		case unicode.In(c, unicode.Mn, unicode.Me), c == '\u200d', c >= 0xfe00 && c <= 0xfe0f, c >= 0x1f3fb && c <= 0x1f3ff, c >= 0xe0020 && c <= 0xe007f:
+			// combining marks, zero width joiner, variation selectors, skin tones and tag characters
+			extend = true
+		case prev == '\u200d':
+			extend = true
+		case c >= 0x1f1e6 && c <= 0x1f1ff && regional%2 == 1:
+			// the second regional indicator of a flag
+			extend = true
+		}
+
+		if c >= 0x1f1e6 && c <= 0x1f1ff {
+			regional++
+		} else {
+			regional = 0
+		}
+
+		if !extend {
+			bounds = append(bounds, i)
+		}
+		prev = c
+	}
+	return bounds
+}
+
+// Levenshtein returns the minimum number of rune insertions, deletions and substitutions to change a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	// one row of the distance matrix is enough, with the shorter string as columns
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d := diag + cost
+			if row[j]+1 < d {
+				d = row[j] + 1
+			}
+			if row[j-1]+1 < d {
+				d = row[j-1] + 1
+			}
+			diag, row[j] = row[j], d
+		}
+	}
+	return row[len(rb)]
+}
+
+// Similarity returns 1 - Levenshtein(a, b)/max(len(a), len(b)) counting runes, in [0, 1].
+// Two empty strings are similar as 1.
+func Similarity(a, b string) float64 {
+	n := utf8.RuneCountInString(a)
+	if m := utf8.RuneCountInString(b); m > n {
+		n = m
+	}
+
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(n)
+}
+
+// RuneWidth returns the number of terminal columns taken by c: 0 for control characters and combining marks,
+// 2 for east asian wide and fullwidth characters and emoji, and 1 for others
+func RuneWidth(c rune) int {
+	switch {
+	case c == 0, c < 32, c >= 0x7f && c < 0xa0, unicode.In(c, unicode.Mn, unicode.Me), c == '\u200d':
+		return 0
+	case c >= 0x1100 && c <= 0x115f, c >= 0x2e80 && c <= 0x303e, c >= 0x3041 && c <= 0x33ff,
+		c >= 0x3400 && c <= 0x4dbf, c >= 0x4e00 && c <= 0x9fff, c >= 0xa000 && c <= 0xa4cf,
+		c >= 0xac00 && c <= 0xd7a3, c >= 0xf900 && c <= 0xfaff, c >= 0xfe30 && c <= 0xfe4f,
+		c >= 0xff00 && c <= 0xff60, c >= 0xffe0 && c <= 0xffe6,
+		c >= 0x1f300 && c <= 0x1f64f, c >= 0x1f900 && c <= 0x1f9ff, c >= 0x20000 && c <= 0x3fffd:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// StringWidth returns the number of terminal columns taken by s
+func StringWidth(s string) int {
+	n := 0
+	for _, c := range s {
+		n += RuneWidth(c)
+	}
+	return n
+}
+
+// PadLeft pads s with pad on the left to width columns, e.g. PadLeft("42", 5, '0') returns 00042
+func PadLeft(s string, width int, pad rune) string {
+	n := padCount(s, width, pad)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(string(pad), n) + s
+}
+
+// PadRight pads s with pad on the right to width columns
+func PadRight(s string, width int, pad rune) string {
+	n := padCount(s, width, pad)
+	if n <= 0 {
+		return s
+	}
+	return s + strings.Repeat(string(pad), n)
+}
+
+// Center pads s with pad on both sides to width columns. The extra pad goes to the right if padding is odd.
+func Center(s string, width int, pad rune) string {
+	n := padCount(s, width, pad)
+	if n <= 0 {
+		return s
+	}
+	left := strings.Repeat(string(pad), n/2)
+	return left + s + strings.Repeat(string(pad), n-n/2)
+}
+
+// padCount returns the number of pads to fill s to width
+func padCount(s string, width int, pad rune) int {
+	w := RuneWidth(pad)
+	if w == 0 {
+		return 0
+	}
+	return (width - StringWidth(s)) / w
+}
+
+// Wrap breaks s into lines of at most width columns at spaces. Words longer than width are broken.
+// Existing line breaks are kept, and spaces at line breaks are removed.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	b := &strings.Builder{}
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		col := 0
+		for _, word := range strings.Fields(line) {
+			w := StringWidth(word)
+			if col > 0 && col+1+w > width {
+				b.WriteByte('\n')
+				col = 0
+			} else if col > 0 {
+				b.WriteByte(' ')
+				col++
+			}
+
+			// break a word longer than width, which always starts at a new line here
+			for w > width {
+				k, cw := 0, 0
+				for j, c := range word {
+					if cw+RuneWidth(c) > width {
+						k = j
+						break
+					}
+					cw += RuneWidth(c)
+				}
+
+				if k == 0 {
+					// a wide rune doesn't fit in width
+					_, k = utf8.DecodeRuneInString(word)
+				}
+				b.WriteString(word[:k])
+				b.WriteByte('\n')
+				word = word[k:]
+				w = StringWidth(word)
+			}
+			b.WriteString(word)
+			col += w
+		}
+	}
+	return b.String()
+}
+
+// NaturalCompare compares a and b like strings.Compare, except that runs of digits are compared by numeric value,
+// e.g. file2 < file10. Equal numbers with more leading zeros come after, e.g. a1 < a01.
+func NaturalCompare(a, b string) int {
+	for len(a) > 0 && len(b) > 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			da, db := digitPrefix(a), digitPrefix(b)
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if len(na) != len(nb) {
+				return compareInt(len(na), len(nb))
+			}
+
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+
+			if len(da) != len(db) {
+				return compareInt(len(da), len(db))
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			return compareInt(int(ra), int(rb))
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return compareInt(len(a), len(b))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
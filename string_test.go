@@ -0,0 +1,81 @@
+package gox_test
+
+import (
+	"sort"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "hello", gox.Truncate("hello", 5, "..."))
+	assert.Equal(t, "he...", gox.Truncate("hello world", 5, "..."))
+	assert.Equal(t, "你好世…", gox.Truncate("你好世界和平", 4, "…"))
+	assert.Equal(t, "..", gox.Truncate("hello", 2, "..."))
+	assert.Equal(t, "", gox.Truncate("hello", 0, ""))
+
+	s := gox.Truncate("😀😃😄😁", 3, "")
+	assert.True(t, utf8.ValidString(s))
+	assert.Equal(t, "😀😃😄", s)
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	family := "👨‍👩‍👧"
+	assert.Equal(t, family+"…", gox.TruncateGraphemes(family+family+family, 2, "…"))
+	assert.Equal(t, "🇺🇸🇯🇵", gox.TruncateGraphemes("🇺🇸🇯🇵🇨🇳", 2, ""))
+	assert.Equal(t, "e\u0301t", gox.TruncateGraphemes("e\u0301te\u0301", 2, ""))
+	assert.Equal(t, "👍🏽", gox.TruncateGraphemes("👍🏽👍🏽", 1, ""))
+	assert.Equal(t, "abc", gox.TruncateGraphemes("abc", 3, "..."))
+	assert.Equal(t, "..", gox.TruncateGraphemes("hello", 2, "..."))
+	assert.Equal(t, "", gox.TruncateGraphemes("hello", 0, "..."))
+	assert.Equal(t, "", gox.TruncateGraphemes("hello", -1, "..."))
+	assert.Equal(t, "", gox.Truncate("hello", -1, "..."))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 3, gox.Levenshtein("kitten", "sitting"))
+	assert.Equal(t, 3, gox.Levenshtein("sitting", "kitten"))
+	assert.Equal(t, 0, gox.Levenshtein("", ""))
+	assert.Equal(t, 4, gox.Levenshtein("", "abcd"))
+	assert.Equal(t, 1, gox.Levenshtein("你好", "你们好"))
+
+	assert.Equal(t, 1.0, gox.Similarity("", ""))
+	assert.Equal(t, 0.0, gox.Similarity("abc", "xyz"))
+	assert.InDelta(t, 0.75, gox.Similarity("8KD3", "8KD8"), 1e-9)
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "00042", gox.PadLeft("42", 5, '0'))
+	assert.Equal(t, "42   ", gox.PadRight("42", 5, ' '))
+	assert.Equal(t, "-ab--", gox.Center("ab", 5, '-'))
+	assert.Equal(t, "你好 ", gox.PadRight("你好", 5, ' '))
+	assert.Equal(t, "hello", gox.PadLeft("hello", 3, ' '))
+	assert.Equal(t, 4, gox.StringWidth("你好"))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, "The quick\nbrown fox\njumps over\nthe lazy\ndog", gox.Wrap(quickFox, 10))
+	assert.Equal(t, "abcd\nefgh\nij", gox.Wrap("abcdefghij", 4))
+	assert.Equal(t, "a\nb c", gox.Wrap("a\nb c", 10))
+	assert.Equal(t, "你好\n世界", gox.Wrap("你好世界", 5))
+	assert.Equal(t, "ab\ncdef\ngh", gox.Wrap("ab cdefgh", 4))
+}
+
+func TestNaturalCompare(t *testing.T) {
+	assert.Equal(t, -1, gox.NaturalCompare("file2", "file10"))
+	assert.Equal(t, 1, gox.NaturalCompare("file10", "file2"))
+	assert.Equal(t, 0, gox.NaturalCompare("file10", "file10"))
+	assert.Equal(t, -1, gox.NaturalCompare("a1", "a01"))
+	assert.Equal(t, -1, gox.NaturalCompare("a", "a1"))
+	assert.Equal(t, -1, gox.NaturalCompare("v1.9.2", "v1.10.0"))
+	assert.Equal(t, 1, gox.NaturalCompare("b1", "a2"))
+	assert.Equal(t, -1, gox.NaturalCompare("x99999999999999999999999", "x100000000000000000000000"))
+
+	files := []string{"img12.png", "img10.png", "img2.png", "img1.png"}
+	sort.Slice(files, func(i, j int) bool {
+		return gox.NaturalCompare(files[i], files[j]) < 0
+	})
+	assert.Equal(t, []string{"img1.png", "img2.png", "img10.png", "img12.png"}, files)
+}
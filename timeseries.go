@@ -0,0 +1,248 @@
+package gox
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TimeSeriesPoint is the aggregation of values in [Time, Time+step)
+type TimeSeriesPoint struct {
+	Time  time.Time `json:"time"`
+	Count int64     `json:"count"`
+	Sum   float64   `json:"sum"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+}
+
+func (p *TimeSeriesPoint) Avg() float64 {
+	if p.Count == 0 {
+		return 0
+	}
+	return p.Sum / float64(p.Count)
+}
+
+func (p *TimeSeriesPoint) merge(b *timeSeriesBucket) {
+	if b.count == 0 {
+		return
+	}
+
+	if p.Count == 0 || b.min < p.Min {
+		p.Min = b.min
+	}
+
+	if p.Count == 0 || b.max > p.Max {
+		p.Max = b.max
+	}
+	p.Count += b.count
+	p.Sum += b.sum
+}
+
+type timeSeriesBucket struct {
+	start int64
+	count int64
+	sum   float64
+	min   float64
+	max   float64
+}
+
+// TimeSeries keeps values of recent time in a ring of fixed resolution buckets, e.g. 3600 buckets of one second for last hour.
+// Values are aggregated into count, sum, min and max while being added.
+type TimeSeries struct {
+	resolution time.Duration
+	clock      Clock
+
+	mu      sync.Mutex
+	buckets []timeSeriesBucket
+}
+
+func NewTimeSeries(resolution time.Duration, size int) *TimeSeries {
+	if resolution <= 0 {
+		panic("resolution should be positive")
+	}
+
+	if size <= 0 {
+		panic("size should be positive")
+	}
+
+	return &TimeSeries{
+		resolution: resolution,
+		clock:      LocalClock(),
+		buckets:    make([]timeSeriesBucket, size),
+	}
+}
+
+func (s *TimeSeries) SetClock(c Clock) {
+	s.mu.Lock()
+	s.clock = c
+	s.mu.Unlock()
+}
+
+func (s *TimeSeries) Resolution() time.Duration {
+	return s.resolution
+}
+
+// Span returns the duration covered by the series
+func (s *TimeSeries) Span() time.Duration {
+	return s.resolution * time.Duration(len(s.buckets))
+}
+
+// Incr adds 1, e.g. for counting generated IDs or decode errors
+func (s *TimeSeries) Incr() {
+	s.Add(1)
+}
+
+func (s *TimeSeries) Add(v float64) {
+	s.mu.Lock()
+	s.add(s.clock.Now(), v)
+	s.mu.Unlock()
+}
+
+// AddAt adds v at t. Values older than the span are dropped.
+func (s *TimeSeries) AddAt(t time.Time, v float64) {
+	s.mu.Lock()
+	s.add(t, v)
+	s.mu.Unlock()
+}
+
+func (s *TimeSeries) add(t time.Time, v float64) {
+	start := s.bucketStart(t)
+	if start <= s.bucketStart(s.clock.Now())-int64(s.Span()) {
+		return
+	}
+
+	b := &s.buckets[s.index(start)]
+	if b.start != start || b.count == 0 {
+		*b = timeSeriesBucket{start: start, min: v, max: v}
+	}
+	b.count++
+	b.sum += v
+	if v < b.min {
+		b.min = v
+	}
+
+	if v > b.max {
+		b.max = v
+	}
+}
+
+func (s *TimeSeries) bucketStart(t time.Time) int64 {
+	n := t.UnixNano()
+	r := int64(s.resolution)
+	start := n / r * r
+	if start > n {
+		start -= r
+	}
+	return start
+}
+
+func (s *TimeSeries) index(start int64) int {
+	i := (start / int64(s.resolution)) % int64(len(s.buckets))
+	if i < 0 {
+		i += int64(len(s.buckets))
+	}
+	return int(i)
+}
+
+// Points aggregates values of last d into points of step, in ascending order of time.
+// step is rounded up to a multiple of resolution, and d is truncated to the span.
+// Points without values are included with zero count.
+func (s *TimeSeries) Points(d, step time.Duration) []*TimeSeriesPoint {
+	if step < s.resolution {
+		step = s.resolution
+	}
+	step = (step + s.resolution - 1) / s.resolution * s.resolution
+
+	if d <= 0 || d > s.Span() {
+		d = s.Span()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := int64(s.resolution)
+	end := s.bucketStart(s.clock.Now()) + r
+	begin := end - int64(d)
+	if m := begin % int64(step); m != 0 {
+		// align points to step, the first point may be partial
+		begin -= m
+		if m < 0 {
+			begin -= int64(step)
+		}
+	}
+
+	var points []*TimeSeriesPoint
+	for t := begin; t < end; t += int64(step) {
+		p := &TimeSeriesPoint{Time: time.Unix(0, t)}
+		for start := t; start < t+int64(step) && start < end; start += r {
+			if b := &s.buckets[s.index(start)]; b.start == start {
+				p.merge(b)
+			}
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// Sum aggregates values of last d into one point
+func (s *TimeSeries) Sum(d time.Duration) *TimeSeriesPoint {
+	if d <= 0 || d > s.Span() {
+		d = s.Span()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	end := s.bucketStart(s.clock.Now()) + int64(s.resolution)
+	p := &TimeSeriesPoint{Time: time.Unix(0, end-int64(d))}
+	for start := end - int64(d); start < end; start += int64(s.resolution) {
+		if b := &s.buckets[s.index(start)]; b.start == start {
+			p.merge(b)
+		}
+	}
+	return p
+}
+
+// TimeSeriesHandler serves points of series in JSON, which is intended for debug endpoints.
+// Query parameters: name filters series, range and step are durations such as 1h and 1m.
+func TimeSeriesHandler(series map[string]*TimeSeries) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var d, step time.Duration
+		var err error
+		if v := q.Get("range"); v != "" {
+			if d, err = time.ParseDuration(v); err != nil {
+				http.Error(w, "invalid range: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if v := q.Get("step"); v != "" {
+			if step, err = time.ParseDuration(v); err != nil {
+				http.Error(w, "invalid step: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		names := q["name"]
+		if len(names) == 0 {
+			for name := range series {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		result := make(map[string][]*TimeSeriesPoint, len(names))
+		for _, name := range names {
+			s := series[name]
+			if s == nil {
+				http.Error(w, "unknown series: "+name, http.StatusNotFound)
+				return
+			}
+			result[name] = s.Points(d, step)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+}
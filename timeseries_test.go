@@ -0,0 +1,61 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock struct {
+	t time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.t
+}
+
+func TestTimeSeries(t *testing.T) {
+	clock := &fixedClock{t: time.Unix(1000, 0)}
+	s := gox.NewTimeSeries(time.Second, 60)
+	s.SetClock(clock)
+
+	for i := 0; i < 70; i++ {
+		s.Add(float64(i))
+		s.Incr()
+		clock.t = clock.t.Add(time.Second)
+	}
+	clock.t = clock.t.Add(-time.Second)
+
+	p := s.Sum(0)
+	assert.Equal(t, int64(120), p.Count)
+	assert.Equal(t, float64(1), p.Min)
+	assert.Equal(t, float64(69), p.Max)
+
+	points := s.Points(10*time.Second, 5*time.Second)
+	require.Len(t, points, 2)
+	assert.Equal(t, time.Unix(1060, 0), points[0].Time)
+	assert.Equal(t, int64(10), points[0].Count)
+	assert.Equal(t, float64(60+61+62+63+64+5), points[0].Sum)
+
+	s.AddAt(time.Unix(1000, 0), 1)
+	assert.Equal(t, int64(120), s.Sum(0).Count)
+
+	t.Run("Handler", func(t *testing.T) {
+		h := gox.TimeSeriesHandler(map[string]*gox.TimeSeries{"ids": s})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/?range=10s&step=5s", nil))
+		require.Equal(t, 200, w.Code)
+		var result map[string][]*gox.TimeSeriesPoint
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Len(t, result["ids"], 2)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/?name=foo", nil))
+		assert.Equal(t, 404, w.Code)
+	})
+}
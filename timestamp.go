@@ -0,0 +1,138 @@
+package gox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Timestamp is unix time in milliseconds, which is the resolution of IDs.
+// It's encoded as a number in JSON, and as time in database where 0 is NULL.
+type Timestamp int64
+
+var _ driver.Valuer = Timestamp(0)
+var _ sql.Scanner = (*Timestamp)(nil)
+
+// sqlTimeLayouts are tried by Timestamp.Scan for text of DATETIME columns, which are in UTC
+var sqlTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// TimestampOf returns t truncated to milliseconds
+func TimestampOf(t time.Time) Timestamp {
+	return Timestamp(t.Unix()*1e3 + int64(t.Nanosecond())/1e6)
+}
+
+// NowTimestamp returns the current time of DefaultClock
+func NowTimestamp() Timestamp {
+	return TimestampOf(DefaultClock().Now())
+}
+
+// ParseTimestamp parses s in RFC3339 with optional fraction, or milliseconds in decimal
+func ParseTimestamp(s string) (Timestamp, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return Timestamp(ms), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s into gox.Timestamp: %v", s, err)
+	}
+	return TimestampOf(t), nil
+}
+
+func (t Timestamp) Time() time.Time {
+	ms := int64(t)
+	return time.Unix(ms/1e3, ms%1e3*1e6)
+}
+
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+// String returns t in RFC3339 with milliseconds in UTC
+func (t Timestamp) String() string {
+	return t.Time().UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// UnmarshalJSON accepts milliseconds in number or string, and RFC3339 strings. null is decoded as 0.
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		*t = 0
+		return nil
+	}
+
+	if len(s) == 0 || s[0] != '"' {
+		// number such as 1577836800000 or 1.5778368e12
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f != float64(int64(f)) {
+			return fmt.Errorf("failed to parse %s into gox.Timestamp", s)
+		}
+
+		if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+			*t = Timestamp(ms)
+		} else {
+			*t = Timestamp(f)
+		}
+		return nil
+	}
+
+	s, err := strconv.Unquote(s)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s into gox.Timestamp: %v", b, err)
+	}
+
+	v, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Scan accepts time.Time, milliseconds in int64, and text of DATETIME/TIMESTAMP columns
+func (t *Timestamp) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*t = 0
+		return nil
+	case time.Time:
+		*t = TimestampOf(v)
+		return nil
+	case int64:
+		*t = Timestamp(v)
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.Timestamp", src)
+	}
+
+	for _, layout := range sqlTimeLayouts {
+		if v, err := time.Parse(layout, s); err == nil {
+			*t = TimestampOf(v)
+			return nil
+		}
+	}
+
+	v, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+func (t Timestamp) Value() (driver.Value, error) {
+	if t == 0 {
+		return nil, nil
+	}
+	return t.Time().UTC(), nil
+}
@@ -0,0 +1,76 @@
+package gox_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp(t *testing.T) {
+	tm := time.Date(2020, time.January, 1, 0, 0, 0, 123456789, time.UTC)
+	ts := gox.TimestampOf(tm)
+	assert.Equal(t, gox.Timestamp(1577836800123), ts)
+	assert.True(t, tm.Truncate(time.Millisecond).Equal(ts.Time()))
+	assert.Equal(t, "2020-01-01T00:00:00.123Z", ts.String())
+
+	before := gox.TimestampOf(time.Date(1969, time.December, 31, 23, 59, 59, 500000000, time.UTC))
+	assert.Equal(t, gox.Timestamp(-500), before)
+	assert.Equal(t, "1969-12-31T23:59:59.500Z", before.String())
+
+	gox.SetDefaultClock(gox.NewMockClock(tm))
+	defer gox.SetDefaultClock(gox.LocalClock())
+	assert.Equal(t, ts, gox.NowTimestamp())
+}
+
+func TestTimestamp_JSON(t *testing.T) {
+	var v struct {
+		CreatedAt gox.Timestamp `json:"created_at"`
+	}
+	inputs := []string{
+		`1577836800123`,
+		`1.577836800123e12`,
+		`"1577836800123"`,
+		`"2020-01-01T00:00:00.123Z"`,
+		`"2020-01-01T08:00:00.123+08:00"`,
+	}
+	for _, in := range inputs {
+		v.CreatedAt = 0
+		require.NoError(t, json.Unmarshal([]byte(`{"created_at":`+in+`}`), &v), in)
+		assert.Equal(t, gox.Timestamp(1577836800123), v.CreatedAt, in)
+	}
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"created_at":1577836800123}`, string(b))
+
+	var ts gox.Timestamp
+	assert.Error(t, json.Unmarshal([]byte(`1.5`), &ts))
+	assert.Error(t, json.Unmarshal([]byte(`"2020-01-01"`), &ts))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &ts))
+}
+
+func TestTimestamp_SQL(t *testing.T) {
+	ts := gox.Timestamp(1577836800123)
+	val, err := ts.Value()
+	require.NoError(t, err)
+
+	var v gox.Timestamp
+	require.NoError(t, v.Scan(val))
+	assert.Equal(t, ts, v)
+	require.NoError(t, v.Scan([]byte("2020-01-01 00:00:00.123")))
+	assert.Equal(t, ts, v)
+	require.NoError(t, v.Scan("2020-01-01T00:00:00.123Z"))
+	assert.Equal(t, ts, v)
+	require.NoError(t, v.Scan(int64(1577836800123)))
+	assert.Equal(t, ts, v)
+
+	require.NoError(t, v.Scan(nil))
+	assert.True(t, v.IsZero())
+	val, err = v.Value()
+	require.NoError(t, err)
+	assert.Nil(t, val)
+}
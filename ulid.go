@@ -0,0 +1,172 @@
+package gox
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ULID is a 128-bit identifier composed of a 48-bit timestamp in milliseconds and 80-bit entropy.
+// Its string form is 26 characters in Crockford's base32, which is lexicographically sortable by time.
+type ULID [16]byte
+
+var defaultULIDGenerator = NewULIDGenerator(nil)
+
+// NewULID returns a ULID of current time generated by the default generator
+func NewULID() ULID {
+	u, err := defaultULIDGenerator.NewAt(time.Now())
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != 26 {
+		return u, errors.New("parse error: invalid length")
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := crockfordValue(s[i])
+		if v < 0 || v >= 32 || (i == 0 && v > 7) {
+			return u, errors.New("parse error")
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+func ULIDFromBytes(b []byte) (ULID, error) {
+	var u ULID
+	if len(b) != len(u) {
+		return u, errors.New("invalid length")
+	}
+	for i := range u {
+		u[i] = b[i]
+	}
+	return u, nil
+}
+
+func (u ULID) String() string {
+	var b [26]byte
+	hi, lo := binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+func (u ULID) Bytes() []byte {
+	return u[:]
+}
+
+// Timestamp returns milliseconds since unix epoch
+func (u ULID) Timestamp() int64 {
+	return int64(u[0])<<40 | int64(u[1])<<32 | int64(binary.BigEndian.Uint32(u[2:6]))
+}
+
+func (u ULID) Time() time.Time {
+	ms := u.Timestamp()
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond))
+}
+
+func (u ULID) IsZero() bool {
+	return u == ULID{}
+}
+
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *ULID) UnmarshalText(text []byte) error {
+	v, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+func (u *ULID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(u) {
+			for i := range u {
+				u[i] = v[i]
+			}
+			return nil
+		}
+		return u.UnmarshalText(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.ULID", src)
+	}
+}
+
+func (u ULID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// ULIDGenerator generates monotonic ULIDs: within the same millisecond, entropy is incremented by 1 instead of regenerated.
+type ULIDGenerator struct {
+	entropy io.Reader
+
+	mu   sync.Mutex
+	last ULID
+}
+
+// NewULIDGenerator creates a generator reading random bytes from entropy. If entropy is nil, crypto/rand is used.
+func NewULIDGenerator(entropy io.Reader) *ULIDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return &ULIDGenerator{entropy: entropy}
+}
+
+func (g *ULIDGenerator) NewAt(t time.Time) (ULID, error) {
+	ms := t.UnixNano() / int64(time.Millisecond)
+	if ms < 0 || ms >= 1<<48 {
+		return ULID{}, errors.New("time is out of range")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var u ULID
+	if ms <= g.last.Timestamp() && !g.last.IsZero() {
+		// keep monotonic even if clock moves backwards
+		u = g.last
+		i := len(u) - 1
+		for ; i >= 6; i-- {
+			u[i]++
+			if u[i] != 0 {
+				break
+			}
+		}
+
+		if i < 6 {
+			return ULID{}, errors.New("entropy overflow")
+		}
+	} else {
+		u[0] = byte(ms >> 40)
+		u[1] = byte(ms >> 32)
+		binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+		if _, err := io.ReadFull(g.entropy, u[6:]); err != nil {
+			return ULID{}, err
+		}
+	}
+	g.last = u
+	return u, nil
+}
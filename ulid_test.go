@@ -0,0 +1,39 @@
+package gox_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestULID(t *testing.T) {
+	g := gox.NewULIDGenerator(bytes.NewReader(make([]byte, 10)))
+	at := time.Unix(0, 1469918176385*int64(time.Millisecond))
+	u1, err := g.NewAt(at)
+	require.NoError(t, err)
+	assert.Equal(t, "01ARYZ6S410000000000000000", u1.String())
+	assert.True(t, u1.Time().Equal(at))
+
+	u2, err := g.NewAt(at)
+	require.NoError(t, err)
+	assert.Equal(t, "01ARYZ6S410000000000000001", u2.String())
+
+	u, err := gox.ParseULID(gox.NewULID().String())
+	require.NoError(t, err)
+	assert.False(t, u.IsZero())
+
+	u, err = gox.ULIDFromBytes(u2.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, u2, u)
+
+	_, err = gox.ParseULID("81ARYZ6S410000000000000000")
+	assert.Error(t, err)
+
+	max, err := gox.ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	require.NoError(t, err)
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", max.String())
+}
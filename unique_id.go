@@ -1,50 +1,231 @@
 package gox
 
 import (
-	"fmt"
-	"math/rand"
+	"encoding/hex"
+	"errors"
 	osuser "os/user"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-func randomString() string {
-	b := &strings.Builder{}
-	addrs, err := GetMacAddrs()
-	if err == nil {
-		for _, a := range addrs {
-			b.WriteString(a)
+var machineFingerprint struct {
+	once sync.Once
+	val  string
+}
+
+// getMachineFingerprint returns mac addresses of physical interfaces, user and ip, which are resolved once as they rarely change
+func getMachineFingerprint() string {
+	machineFingerprint.once.Do(func() {
+		b := &strings.Builder{}
+		addrs, err := GetMacAddrs(PhysicalInterface)
+		if err == nil {
+			for _, a := range addrs {
+				b.WriteString(a)
+			}
 		}
-	}
 
-	u, err := osuser.Current()
-	if err == nil {
-		b.WriteString(u.Name)
-		b.WriteString(u.Username)
-		b.WriteString(u.Gid)
-		b.WriteString(u.HomeDir)
-		b.WriteString(u.Uid)
-	}
+		u, err := osuser.Current()
+		if err == nil {
+			b.WriteString(u.Name)
+			b.WriteString(u.Username)
+			b.WriteString(u.Gid)
+			b.WriteString(u.HomeDir)
+			b.WriteString(u.Uid)
+		}
+
+		b.WriteString(GetIP().String())
+		machineFingerprint.val = b.String()
+	})
+	return machineFingerprint.val
+}
 
-	b.WriteString(GetIP().String())
+var uniqueIDCounter Counter
+
+// randomString mixes 16 bytes from RandSource with machine fingerprint, time and a counter.
+// Unique ids derived from it are as unpredictable as 128 random bits, even if machine facts are known.
+func randomString() string {
+	var r [16]byte
+	randRead(r[:])
+
+	b := &strings.Builder{}
+	b.WriteString(getMachineFingerprint())
 	b.WriteString(time.Now().String())
-	b.WriteString(NextID().ShortString())
-	b.WriteString(fmt.Sprint(rand.Int()))
+	b.WriteString(strconv.FormatInt(uniqueIDCounter.Next(), 36))
+	b.WriteString(hex.EncodeToString(r[:]))
 	return b.String()
 }
 
+// UniqueID returns 40 hex characters with 128-bit entropy from RandSource
 func UniqueID() string {
 	return SHA1(randomString())
 }
 
+// UniqueID32 returns 32 hex characters with 128-bit entropy from RandSource
 func UniqueID32() string {
 	return MD5(randomString())
 }
 
+// UniqueID40 returns 40 hex characters with 128-bit entropy from RandSource
 func UniqueID40() string {
 	return SHA1(randomString())
 }
 
+// UniqueID64 returns 64 hex characters with 128-bit entropy from RandSource
 func UniqueID64() string {
 	return SHA256(randomString())
 }
+
+// UniqueIDWithPrefix returns prefix and UniqueID joined by underscore, e.g. sess_<40 hex characters>.
+// Prefix consists of lowercase letters, digits and underscores, starting with a letter.
+func UniqueIDWithPrefix(prefix string) string {
+	return withUniqueIDPrefix(prefix, UniqueID())
+}
+
+func UniqueID32WithPrefix(prefix string) string {
+	return withUniqueIDPrefix(prefix, UniqueID32())
+}
+
+func UniqueID40WithPrefix(prefix string) string {
+	return withUniqueIDPrefix(prefix, UniqueID40())
+}
+
+func UniqueID64WithPrefix(prefix string) string {
+	return withUniqueIDPrefix(prefix, UniqueID64())
+}
+
+func withUniqueIDPrefix(prefix, id string) string {
+	if !isValidIDPrefix(prefix) {
+		panic("invalid id prefix: " + prefix)
+	}
+	return prefix + "_" + id
+}
+
+// TrimUniqueIDPrefix checks s is generated by UniqueID*WithPrefix with prefix, and returns the unique id part
+func TrimUniqueIDPrefix(s, prefix string) (string, error) {
+	if !strings.HasPrefix(s, prefix+"_") {
+		return "", errors.New("prefix mismatch")
+	}
+
+	id := s[len(prefix)+1:]
+	switch len(id) {
+	case 32, 40, 64:
+	default:
+		return "", errors.New("invalid length")
+	}
+
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return "", errors.New("invalid character")
+		}
+	}
+	return id, nil
+}
+
+// UniqueTimeID returns 40 hex characters of 48-bit unix milliseconds followed by 112 random bits from RandSource.
+// Creation time can be extracted by UniqueIDTime, and ids sort by time in milliseconds.
+func UniqueTimeID() string {
+	var b [20]byte
+	randRead(b[6:])
+
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// UniqueIDTime returns the creation time of s returned by UniqueTimeID
+func UniqueIDTime(s string) (time.Time, error) {
+	if len(s) != 40 {
+		return time.Time{}, errors.New("invalid length")
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var ms int64
+	for _, v := range b[:6] {
+		ms = ms<<8 | int64(v)
+	}
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond)), nil
+}
+
+// NanoIDAlphabet is the url-safe alphabet of NanoID
+const NanoIDAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+// RandomID returns length characters chosen uniformly from alphabet with RandSource, like NanoID.
+// Length defaults to 21 and alphabet defaults to NanoIDAlphabet, which gives 126-bit entropy.
+// Alphabet should have at most 256 single-byte characters.
+func RandomID(length int, alphabet string) string {
+	if length <= 0 {
+		length = 21
+	}
+
+	if len(alphabet) == 0 {
+		alphabet = NanoIDAlphabet
+	}
+
+	if len(alphabet) > 256 {
+		panic("alphabet is longer than 256")
+	}
+
+	// reject random bytes beyond the smallest mask covering alphabet, so that every character is equally likely
+	mask := byte(1)
+	for int(mask) < len(alphabet)-1 {
+		mask = mask<<1 | 1
+	}
+
+	id := make([]byte, 0, length)
+	buf := make([]byte, length*2)
+	for {
+		randRead(buf)
+
+		for _, c := range buf {
+			if i := int(c & mask); i < len(alphabet) {
+				id = append(id, alphabet[i])
+				if len(id) == length {
+					return string(id)
+				}
+			}
+		}
+	}
+}
+
+const ErrNoUniqueShortID ErrorString = "no unique short id found"
+
+const shortUniqueIDMaxAttempts = 5
+
+// ShortUniqueID returns a random id of n base62 characters which doesn't exist, e.g. a slug of short url.
+// exists reports whether an id is taken, e.g. by looking up database. Collisions and errors are retried with
+// exponential backoff up to 5 attempts, after that ErrNoUniqueShortID or the last error is returned.
+func ShortUniqueID(n int, exists func(id string) (bool, error)) (string, error) {
+	if n <= 0 {
+		panic("n should be positive")
+	}
+
+	var err error
+	backoff := 5 * time.Millisecond
+	for i := 0; i < shortUniqueIDMaxAttempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		id := RandomID(n, shortAlphabet)
+		var found bool
+		found, err = exists(id)
+		if err == nil && !found {
+			return id, nil
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+	return "", ErrNoUniqueShortID
+}
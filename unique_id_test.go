@@ -0,0 +1,128 @@
+package gox_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueID(t *testing.T) {
+	assert.Len(t, gox.UniqueID(), 40)
+	assert.Len(t, gox.UniqueID32(), 32)
+	assert.Len(t, gox.UniqueID40(), 40)
+	assert.Len(t, gox.UniqueID64(), 64)
+	assert.NotEqual(t, gox.UniqueID(), gox.UniqueID())
+}
+
+func BenchmarkUniqueID(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gox.UniqueID()
+	}
+}
+
+func TestRandomID(t *testing.T) {
+	id := gox.RandomID(0, "")
+	assert.Len(t, id, 21)
+	for _, c := range id {
+		assert.Contains(t, gox.NanoIDAlphabet, string(c))
+	}
+	assert.NotEqual(t, id, gox.RandomID(0, ""))
+
+	id = gox.RandomID(100, "abc")
+	assert.Len(t, id, 100)
+	for _, c := range id {
+		assert.Contains(t, "abc", string(c))
+	}
+
+	assert.Equal(t, "xxxx", gox.RandomID(4, "x"))
+}
+
+func TestUniqueTimeID(t *testing.T) {
+	id := gox.UniqueTimeID()
+	assert.Len(t, id, 40)
+	tm, err := gox.UniqueIDTime(id)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), tm, time.Second)
+
+	_, err = gox.UniqueIDTime(gox.UniqueID32())
+	assert.Error(t, err)
+}
+
+func TestShortUniqueID(t *testing.T) {
+	var tried []string
+	id, err := gox.ShortUniqueID(6, func(id string) (bool, error) {
+		tried = append(tried, id)
+		return len(tried) < 3, nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, id, 6)
+	assert.Len(t, tried, 3)
+	assert.Equal(t, tried[2], id)
+
+	_, err = gox.ShortUniqueID(6, func(id string) (bool, error) {
+		return true, nil
+	})
+	assert.Equal(t, gox.ErrNoUniqueShortID, err)
+
+	dbErr := errors.New("db error")
+	_, err = gox.ShortUniqueID(6, func(id string) (bool, error) {
+		return false, dbErr
+	})
+	assert.Equal(t, dbErr, err)
+}
+
+func TestUniqueIDWithPrefix(t *testing.T) {
+	for _, id := range []string{
+		gox.UniqueIDWithPrefix("sess"),
+		gox.UniqueID32WithPrefix("sess"),
+		gox.UniqueID40WithPrefix("sess"),
+		gox.UniqueID64WithPrefix("sess"),
+	} {
+		v, err := gox.TrimUniqueIDPrefix(id, "sess")
+		require.NoError(t, err)
+		assert.Equal(t, id[5:], v)
+
+		_, err = gox.TrimUniqueIDPrefix(id, "inv")
+		assert.Error(t, err)
+	}
+
+	_, err := gox.TrimUniqueIDPrefix("sess_xyz", "sess")
+	assert.Error(t, err)
+	assert.Panics(t, func() {
+		gox.UniqueIDWithPrefix("Sess")
+	})
+}
+
+func BenchmarkRandomIDParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			gox.RandomID(0, "")
+		}
+	})
+}
+
+func TestToken(t *testing.T) {
+	assert.Len(t, gox.Token(32), 43)
+	assert.NotEqual(t, gox.Token(16), gox.Token(16))
+	b, err := hex.DecodeString(gox.TokenHex(20))
+	assert.NoError(t, err)
+	assert.Len(t, b, 20)
+}
+
+func TestRandomString(t *testing.T) {
+	for _, a := range []gox.Alphabet{gox.Numeric, gox.Hex, gox.Alphanumeric, gox.Base62, gox.NoLookalikes} {
+		s := gox.RandomString(32, a)
+		assert.Len(t, s, 32)
+		for _, c := range s {
+			assert.True(t, strings.ContainsRune(string(a), c), s)
+		}
+	}
+	assert.Equal(t, "", gox.RandomString(0, gox.Hex))
+	assert.Panics(t, func() { gox.RandomString(8, "") })
+}
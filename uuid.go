@@ -0,0 +1,219 @@
+package gox
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// UUID is a RFC 4122 universally unique identifier
+type UUID [16]byte
+
+var uuidV7 struct {
+	sync.Mutex
+	lastMs int64
+	seq    uint16
+}
+
+// Namespaces of name-based UUIDs defined in RFC 4122
+var (
+	UUIDNamespaceDNS  = UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	UUIDNamespaceURL  = UUID{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	UUIDNamespaceOID  = UUID{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	UUIDNamespaceX500 = UUID{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// NewUUIDv4 returns a random UUID with 122 bits from crypto/rand
+func NewUUIDv4() UUID {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err)
+	}
+	u.setVersion(4)
+	return u
+}
+
+// NewUUIDv5 returns a UUID derived from SHA-1 of namespace and name, which is the same for the same input
+func NewUUIDv5(namespace UUID, name string) UUID {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	var u UUID
+	sum := h.Sum(nil)
+	for i := range u {
+		u[i] = sum[i]
+	}
+	u.setVersion(5)
+	return u
+}
+
+func (u *UUID) setVersion(v byte) {
+	u[6] = u[6]&0x0f | v<<4
+	u[8] = u[8]&0x3f | 0x80
+}
+
+// NewUUIDv7 returns a version 7 UUID, which starts with 48-bit unix milliseconds and is sortable by time.
+// The 12-bit rand_a field is a counter within the same millisecond, starting from a random value.
+func NewUUIDv7() UUID {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err)
+	}
+
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	uuidV7.Lock()
+	if ms <= uuidV7.lastMs {
+		ms = uuidV7.lastMs
+		uuidV7.seq++
+		if uuidV7.seq > 0xfff {
+			// borrow next millisecond once counter is exhausted
+			ms++
+			uuidV7.seq = 0
+		}
+	} else {
+		uuidV7.seq = binary.BigEndian.Uint16(u[6:8]) & 0x7ff
+	}
+	uuidV7.lastMs = ms
+	seq := uuidV7.seq
+	uuidV7.Unlock()
+
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	binary.BigEndian.PutUint16(u[6:8], 0x7000|seq)
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// IDToUUID embeds id into a version 8 UUID, which keeps the order of IDs.
+// ID bits are placed in the 48-bit, 12-bit and the low 4 bits of the variant byte, and the rest are zero.
+func IDToUUID(id ID) UUID {
+	var u UUID
+	v := uint64(id)
+	binary.BigEndian.PutUint32(u[0:4], uint32(v>>32))
+	binary.BigEndian.PutUint16(u[4:6], uint16(v>>16))
+	binary.BigEndian.PutUint16(u[6:8], 0x8000|uint16(v>>4)&0xfff)
+	u[8] = 0x80 | byte(v&0xf)
+	return u
+}
+
+// UUIDToID extracts ID embedded by IDToUUID
+func UUIDToID(u UUID) (ID, error) {
+	if u.Version() != 8 || u[8]&0xf0 != 0x80 {
+		return 0, errors.New("uuid doesn't embed an id")
+	}
+
+	for _, b := range u[9:] {
+		if b != 0 {
+			return 0, errors.New("uuid doesn't embed an id")
+		}
+	}
+
+	v := uint64(binary.BigEndian.Uint32(u[0:4]))<<32 |
+		uint64(binary.BigEndian.Uint16(u[4:6]))<<16 |
+		uint64(binary.BigEndian.Uint16(u[6:8])&0xfff)<<4 |
+		uint64(u[8]&0xf)
+	return ID(v), nil
+}
+
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, errors.New("parse error")
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return u, errors.New("parse error: invalid length")
+	}
+
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, errors.New("parse error")
+	}
+	return u, nil
+}
+
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the time of version 7 UUID, or zero time for other versions
+func (u UUID) Time() time.Time {
+	if u.Version() != 7 {
+		return time.Time{}
+	}
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(binary.BigEndian.Uint32(u[2:6]))
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond))
+}
+
+// IsValid reports whether u has RFC 4122 variant and a known version
+func (u UUID) IsValid() bool {
+	v := u.Version()
+	return u[8]&0xc0 == 0x80 && v >= 1 && v <= 8
+}
+
+// IsValidUUID reports whether s is a UUID string with RFC 4122 variant and a known version
+func IsValidUUID(s string) bool {
+	u, err := ParseUUID(s)
+	return err == nil && u.IsValid()
+}
+
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *UUID) UnmarshalText(text []byte) error {
+	v, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+func (u *UUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(u) {
+			for i := range u {
+				u[i] = v[i]
+			}
+			return nil
+		}
+		return u.UnmarshalText(v)
+	default:
+		return fmt.Errorf("failed to parse %v into gox.UUID", src)
+	}
+}
+
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
@@ -0,0 +1,63 @@
+package gox_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gopub/gox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUUIDv7(t *testing.T) {
+	var last string
+	for i := 0; i < 1000; i++ {
+		u := gox.NewUUIDv7()
+		assert.Equal(t, 7, u.Version())
+		assert.Equal(t, byte(0x80), u[8]&0xc0)
+		s := u.String()
+		assert.True(t, s > last, s)
+		last = s
+	}
+
+	u, err := gox.ParseUUID(last)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), u.Time(), time.Second)
+}
+
+func TestIDToUUID(t *testing.T) {
+	var last gox.UUID
+	for _, id := range []gox.ID{0, 1, 15, 16, 1 << 20, math.MaxInt64} {
+		u := gox.IDToUUID(id)
+		assert.Equal(t, 8, u.Version())
+		assert.True(t, u.String() > last.String() || id == 0)
+		last = u
+
+		v, err := gox.UUIDToID(u)
+		require.NoError(t, err)
+		assert.Equal(t, id, v)
+	}
+
+	_, err := gox.UUIDToID(gox.NewUUIDv7())
+	assert.Error(t, err)
+}
+
+func TestNewUUIDv4(t *testing.T) {
+	u := gox.NewUUIDv4()
+	assert.Equal(t, 4, u.Version())
+	assert.True(t, u.IsValid())
+	assert.NotEqual(t, u, gox.NewUUIDv4())
+	assert.True(t, gox.IsValidUUID(u.String()))
+}
+
+func TestNewUUIDv5(t *testing.T) {
+	u := gox.NewUUIDv5(gox.UUIDNamespaceDNS, "python.org")
+	assert.Equal(t, "886313e1-3b8a-5372-9b90-0c9aee199e5d", u.String())
+	assert.Equal(t, 5, u.Version())
+	assert.Equal(t, u, gox.NewUUIDv5(gox.UUIDNamespaceDNS, "python.org"))
+
+	assert.False(t, gox.IsValidUUID("886313e1-3b8a-0372-9b90-0c9aee199e5d"))
+	assert.False(t, gox.IsValidUUID("886313e1-3b8a-5372-1b90-0c9aee199e5d"))
+	assert.False(t, gox.IsValidUUID("886313e1-3b8a-5372-9b90"))
+}